@@ -0,0 +1,73 @@
+#!/bin/bash
+
+setUp() {
+  rm test*.yml 2>/dev/null || true
+  rm test*.json 2>/dev/null || true
+  rm test*.txt 2>/dev/null || true
+}
+
+testArgumentString() {
+  X=$(./yq -n --arg name=cat '.name = $name')
+  assertEquals "name: cat" "$X"
+
+  X=$(./yq ea -n --arg name=cat '.name = $name')
+  assertEquals "name: cat" "$X"
+}
+
+testArgumentStringIsNotParsed() {
+  X=$(./yq -n --arg num=5 '$num | tag')
+  assertEquals '!!str' "$X"
+}
+
+testArgumentJsonAndYaml() {
+  X=$(./yq -n --argjson a='{"b": [1, 2]}' --argyaml c='d: true' '[$a.b[1], $c.d]' -o=json -I=0)
+  assertEquals '[2,true]' "$X"
+}
+
+testArgumentRawFile() {
+  cat >test.txt <<EOL
+some text
+EOL
+
+  X=$(./yq -n --rawfile text=test.txt '$text')
+  assertEquals "some text" "$X"
+}
+
+testArgumentSlurpFile() {
+  cat >test.json <<EOL
+{"a": 1}
+{"a": 2}
+EOL
+  cat >test.yml <<EOL
+a: 3
+---
+a: 4
+EOL
+
+  X=$(./yq -n --slurpfile j=test.json --slurpfile y=test.yml '[$j[].a, $y[].a]' -o=json -I=0)
+  assertEquals '[1,2,3,4]' "$X"
+}
+
+testArgumentsVariable() {
+  X=$(./yq -n --arg a=cat --args '$ARGS' dog frog -o=json -I=0)
+  assertEquals '{"positional":["dog","frog"],"named":{"a":"cat"}}' "$X"
+}
+
+testArgumentsReadsStdIn() {
+  X=$(echo "a: cat" | ./yq --args '.a + " " + $ARGS.positional[0]' dog)
+  assertEquals "cat dog" "$X"
+}
+
+testArgumentBadFormat() {
+  result=$(./yq -n --arg cat '.' 2>&1)
+  assertEquals 1 $?
+  assertEquals "Error: --arg expects name=value, but got 'cat'" "$result"
+}
+
+testArgumentBadJson() {
+  result=$(./yq -n --argjson a='{"b"' '.' 2>&1)
+  assertEquals 1 $?
+  assertEquals "Error: could not decode the value of \$a: json: object of object unexpected end of JSON input" "$result"
+}
+
+source ./scripts/shunit2
@@ -182,6 +182,28 @@ EOL
   assertEquals "Error: bad file 'test.yml': XML syntax error on line 7: invalid character entity &writer;" "$X"
 }
 
+testInputJsonStream() {
+  cat >test.yml <<EOL
+{"a": [1, {"b": "cat"}], "c": "dog"}
+EOL
+
+  read -r -d '' expected << EOM
+[["a",0],1]
+[["a",1,"b"],"cat"]
+[["a",1,"b"]]
+[["a",1]]
+EOM
+
+  X=$(./yq --stream -p=json -o=json -I=0 'select(.[0][0] == "a")' test.yml)
+  assertEquals "$expected" "$X"
+
+  X=$(./yq ea --stream -p=json -o=json -I=0 'fromstream(.)' test.yml)
+  assertEquals '{"a":[1,{"b":"cat"}],"c":"dog"}' "$X"
+
+  X=$(./yq ea --stream -p=json -o=json -I=0 'fromstream(. as $event | 1 | truncate_stream($event | select(.[0][0] == "a")))' test.yml)
+  assertEquals '[1,{"b":"cat"}]' "$X"
+}
+
 testInputXmlGithubAction() {
   cat >test.yml <<EOL
 <cat legs="4">BiBi</cat>
@@ -31,3 +31,15 @@ var completedSuccessfully = false
 var forceExpression = ""
 
 var expressionFile = ""
+
+var streamInput = false
+
+// named arguments, each given as name=value
+var stringArguments = []string{}
+var jsonArguments = []string{}
+var yamlArguments = []string{}
+var rawFileArguments = []string{}
+var slurpFileArguments = []string{}
+
+// when set, the arguments after the expression are positional arguments ($ARGS.positional), not files
+var positionalArguments = false
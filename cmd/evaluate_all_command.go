@@ -25,6 +25,9 @@ yq ea '. as $item ireduce ({}; . * $item )' file1.yml file2.yml ...
 # Pipe from STDIN
 ## use '-' as a filename to pipe from STDIN
 cat file2.yml | yq ea '.a.b' file1.yml - file3.yml
+
+# Reassemble the value under the "items" key of a large json file from its stream events
+yq ea --stream -p=json 'fromstream(. as $event | 1 | truncate_stream($event | select(.[0][0] == "items")))' large.json
 `,
 		Long: `yq is a portable command-line YAML processor (https://github.com/mikefarah/yq/) 
 See https://mikefarah.gitbook.io/yq/ for detailed documentation and examples.
@@ -36,6 +39,7 @@ Note that it consumes more memory than eval.
 `,
 		RunE: evaluateAll,
 	}
+	addStreamFlag(cmdEvalAll)
 	return cmdEvalAll
 }
 func evaluateAll(cmd *cobra.Command, args []string) (cmdError error) {
@@ -46,7 +50,7 @@ func evaluateAll(cmd *cobra.Command, args []string) (cmdError error) {
 
 	var err error
 
-	expression, args, err := initCommand(cmd, args)
+	expression, args, variables, err := initCommand(cmd, args)
 	if err != nil {
 		return err
 	}
@@ -79,8 +83,17 @@ func evaluateAll(cmd *cobra.Command, args []string) (cmdError error) {
 	if err != nil {
 		return err
 	}
+	if streamInput {
+		if writeInplace {
+			return errors.New("cannot use --stream with --inplace")
+		}
+		decoder, err = configureStreamDecoder(decoder)
+		if err != nil {
+			return err
+		}
+	}
 
-	printerWriter, err := configurePrinterWriter(format, out)
+	printerWriter, err := configurePrinterWriter(format, out, variables)
 	if err != nil {
 		return err
 	}
@@ -105,11 +118,14 @@ func evaluateAll(cmd *cobra.Command, args []string) (cmdError error) {
 	}
 
 	allAtOnceEvaluator := yqlib.NewAllAtOnceEvaluator()
+	allAtOnceEvaluator.SetVariables(variables)
 
 	switch len(args) {
 	case 0:
 		if nullInput {
-			err = yqlib.NewStreamEvaluator().EvaluateNew(processExpression(expression), printer)
+			streamEvaluator := yqlib.NewStreamEvaluator()
+			streamEvaluator.SetVariables(variables)
+			err = streamEvaluator.EvaluateNew(processExpression(expression), printer)
 		} else {
 			cmd.Println(cmd.UsageString())
 			return nil
@@ -30,6 +30,9 @@ yq e -n '.a.b.c = "cat"'
 
 # Update a file inplace
 yq e '.a.b = "cool"' -i file.yaml 
+
+# Process a large json file as a stream of [path, leaf] events
+yq e --stream -p=json -o=json -I=0 'select(.[0][0] == "items")' large.json
 `,
 		Long: `yq is a portable command-line YAML processor (https://github.com/mikefarah/yq/) 
 See https://mikefarah.gitbook.io/yq/ for detailed documentation and examples.
@@ -39,9 +42,15 @@ This command iterates over each yaml document from each given file, applies the
 expression and prints the result in sequence.`,
 		RunE: evaluateSequence,
 	}
+	addStreamFlag(cmdEvalSequence)
 	return cmdEvalSequence
 }
 
+// the stream flag is not persistent, as it does not apply to every sub command.
+func addStreamFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&streamInput, "stream", "", false, "parse the input as a stream of [path, leaf] events (like tostream), without loading whole json documents into memory. Other formats are converted one document at a time, and eval-all keeps all the events in memory.")
+}
+
 func processExpression(expression string) string {
 
 	if prettyPrint && expression == "" {
@@ -62,7 +71,7 @@ func evaluateSequence(cmd *cobra.Command, args []string) (cmdError error) {
 
 	var err error
 
-	expression, args, err := initCommand(cmd, args)
+	expression, args, variables, err := initCommand(cmd, args)
 	if err != nil {
 		return err
 	}
@@ -89,7 +98,7 @@ func evaluateSequence(cmd *cobra.Command, args []string) (cmdError error) {
 		return err
 	}
 
-	printerWriter, err := configurePrinterWriter(format, out)
+	printerWriter, err := configurePrinterWriter(format, out, variables)
 	if err != nil {
 		return err
 	}
@@ -101,7 +110,17 @@ func evaluateSequence(cmd *cobra.Command, args []string) (cmdError error) {
 	if err != nil {
 		return err
 	}
+	if streamInput {
+		if writeInplace {
+			return errors.New("cannot use --stream with --inplace")
+		}
+		decoder, err = configureStreamDecoder(decoder)
+		if err != nil {
+			return err
+		}
+	}
 	streamEvaluator := yqlib.NewStreamEvaluator()
+	streamEvaluator.SetVariables(variables)
 
 	if frontMatter != "" {
 		yqlib.GetLogger().Debug("using front matter handler")
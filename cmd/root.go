@@ -25,6 +25,10 @@ yq -i '.stuff = "foo"' myfile.yml
 
 # print contents of sample.json as idiomatic YAML
 yq -P sample.json
+
+# pass values in as variables, rather than through the environment
+# (note these are given as name=value, where jq uses --arg name value)
+yq --arg name=cat --argjson tags='["a", "b"]' '.name = $name | .tags = $tags' myfile.yml
 `,
 
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -102,6 +106,7 @@ yq -P sample.json
 
 	rootCmd.PersistentFlags().IntVarP(&indent, "indent", "I", 2, "sets indent level for output")
 	rootCmd.Flags().BoolVarP(&version, "version", "V", false, "Print version information and quit")
+	addStreamFlag(rootCmd)
 	rootCmd.PersistentFlags().BoolVarP(&writeInplace, "inplace", "i", false, "update the file inplace of first file given.")
 	rootCmd.PersistentFlags().VarP(unwrapScalarFlag, "unwrapScalar", "r", "unwrap scalar, print the value with no quotes, colors or comments. Defaults to true for yaml")
 	rootCmd.PersistentFlags().Lookup("unwrapScalar").NoOptDefVal = "true"
@@ -119,6 +124,14 @@ yq -P sample.json
 	rootCmd.PersistentFlags().StringVarP(&splitFileExpFile, "split-exp-file", "", "", "Use a file to specify the split-exp expression.")
 
 	rootCmd.PersistentFlags().StringVarP(&expressionFile, "from-file", "", "", "Load expression from specified file.")
+	rootCmd.PersistentFlags().BoolVarP(&yqlib.ConfiguredSecurityPreferences.DisableEnvOps, "security-disable-env-ops", "", false, "Disable env related operations (env, strenv, envsubst, getenv and $ENV).")
+
+	rootCmd.PersistentFlags().StringArrayVar(&stringArguments, "arg", []string{}, "set the variable $name to the string value, given as name=value (unlike jq's --arg name value). Can be given more than once.")
+	rootCmd.PersistentFlags().StringArrayVar(&jsonArguments, "argjson", []string{}, "set the variable $name to the json value, given as name=value (unlike jq's --argjson name value). Can be given more than once.")
+	rootCmd.PersistentFlags().StringArrayVar(&yamlArguments, "argyaml", []string{}, "set the variable $name to the yaml value, given as name=value. Can be given more than once.")
+	rootCmd.PersistentFlags().StringArrayVar(&rawFileArguments, "rawfile", []string{}, "set the variable $name to the contents of the file as a string, given as name=filename. Can be given more than once.")
+	rootCmd.PersistentFlags().StringArrayVar(&slurpFileArguments, "slurpfile", []string{}, "set the variable $name to an array of all the documents in the file, given as name=filename. Can be given more than once.")
+	rootCmd.PersistentFlags().BoolVarP(&positionalArguments, "args", "", false, "treat the arguments after the expression as strings in $ARGS.positional, rather than files. Input is read from STDIN.")
 
 	rootCmd.AddCommand(
 		createEvaluateSequenceCommand(),
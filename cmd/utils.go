@@ -1,16 +1,19 @@
 package cmd
 
 import (
+	"container/list"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/mikefarah/yq/v4/pkg/yqlib"
 	"github.com/spf13/cobra"
 	"gopkg.in/op/go-logging.v1"
 )
 
-func initCommand(cmd *cobra.Command, args []string) (string, []string, error) {
+func initCommand(cmd *cobra.Command, args []string) (string, []string, map[string]*list.List, error) {
 	cmd.SilenceUsage = true
 
 	fileInfo, _ := os.Stdout.Stat()
@@ -19,15 +22,15 @@ func initCommand(cmd *cobra.Command, args []string) (string, []string, error) {
 		colorsEnabled = true
 	}
 
-	expression, args, err := processArgs(args)
+	expression, args, positional, err := processArgs(args)
 	if err != nil {
-		return "", nil, err
+		return "", nil, nil, err
 	}
 
 	if splitFileExpFile != "" {
 		splitExpressionBytes, err := os.ReadFile(splitFileExpFile)
 		if err != nil {
-			return "", nil, err
+			return "", nil, nil, err
 		}
 		splitFileExp = string(splitExpressionBytes)
 	}
@@ -38,22 +41,109 @@ func initCommand(cmd *cobra.Command, args []string) (string, []string, error) {
 	}
 
 	if writeInplace && (len(args) == 0 || args[0] == "-") {
-		return "", nil, fmt.Errorf("write inplace flag only applicable when giving an expression and at least one file")
+		return "", nil, nil, fmt.Errorf("write inplace flag only applicable when giving an expression and at least one file")
 	}
 
 	if frontMatter != "" && len(args) == 0 {
-		return "", nil, fmt.Errorf("front matter flag only applicable when giving an expression and at least one file")
+		return "", nil, nil, fmt.Errorf("front matter flag only applicable when giving an expression and at least one file")
 	}
 
 	if writeInplace && splitFileExp != "" {
-		return "", nil, fmt.Errorf("write inplace cannot be used with split file")
+		return "", nil, nil, fmt.Errorf("write inplace cannot be used with split file")
 	}
 
 	if nullInput && len(args) > 0 {
-		return "", nil, fmt.Errorf("cannot pass files in when using null-input flag")
+		return "", nil, nil, fmt.Errorf("cannot pass files in when using null-input flag")
 	}
 
-	return expression, args, nil
+	variables, err := configureArguments(positional)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	return expression, args, variables, nil
+}
+
+func parseNamedArgument(flag string, argument string) (string, string, error) {
+	name, value, found := strings.Cut(argument, "=")
+	if !found || name == "" {
+		return "", "", fmt.Errorf("--%v expects name=value, but got '%v'", flag, argument)
+	}
+	return name, value, nil
+}
+
+// slurpFileDecoder reads .json files as a stream of json values (like jq), anything else as yaml.
+func slurpFileDecoder(filename string) yqlib.Decoder {
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		return yqlib.NewJSONDecoder()
+	}
+	return yqlib.NewYamlDecoder(yqlib.LoadYamlPreferences)
+}
+
+// configureArguments returns the --arg (and friends) flags as variables, as well as $ARGS.
+func configureArguments(positional []string) (map[string]*list.List, error) {
+	arguments := yqlib.NewCommandLineArguments()
+
+	for _, argument := range stringArguments {
+		name, value, err := parseNamedArgument("arg", argument)
+		if err != nil {
+			return nil, err
+		}
+		arguments.AddString(name, value)
+	}
+
+	for _, argument := range jsonArguments {
+		name, value, err := parseNamedArgument("argjson", argument)
+		if err != nil {
+			return nil, err
+		}
+		err = arguments.AddDecoded(name, strings.NewReader(value), yqlib.NewJSONDecoder(), false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, argument := range yamlArguments {
+		name, value, err := parseNamedArgument("argyaml", argument)
+		if err != nil {
+			return nil, err
+		}
+		err = arguments.AddDecoded(name, strings.NewReader(value), yqlib.NewYamlDecoder(yqlib.LoadYamlPreferences), false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, argument := range rawFileArguments {
+		name, filename, err := parseNamedArgument("rawfile", argument)
+		if err != nil {
+			return nil, err
+		}
+		content, err := os.ReadFile(filename) // #nosec
+		if err != nil {
+			return nil, err
+		}
+		arguments.AddString(name, string(content))
+	}
+
+	for _, argument := range slurpFileArguments {
+		name, filename, err := parseNamedArgument("slurpfile", argument)
+		if err != nil {
+			return nil, err
+		}
+		file, err := os.Open(filename) // #nosec
+		if err != nil {
+			return nil, err
+		}
+		err = arguments.AddDecoded(name, file, slurpFileDecoder(filename), true)
+		yqlib.SafelyCloseReader(file)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	arguments.AddPositional(positional...)
+	return arguments.Variables(), nil
 }
 
 func configureDecoder(evaluateTogether bool) (yqlib.Decoder, error) {
@@ -78,7 +168,20 @@ func configureDecoder(evaluateTogether bool) (yqlib.Decoder, error) {
 	return yqlib.NewYamlDecoder(prefs), nil
 }
 
-func configurePrinterWriter(format yqlib.PrinterOutputFormat, out io.Writer) (yqlib.PrinterWriter, error) {
+// configureStreamDecoder returns a decoder that reads the input as stream events (see tostream).
+// Json is read token by token, other formats are converted one document at a time.
+func configureStreamDecoder(decoder yqlib.Decoder) (yqlib.Decoder, error) {
+	yqlibInputFormat, err := yqlib.InputFormatFromString(inputFormat)
+	if err != nil {
+		return nil, err
+	}
+	if yqlibInputFormat == yqlib.JsonInputFormat {
+		return yqlib.NewJSONStreamDecoder(), nil
+	}
+	return yqlib.NewStreamDecoder(decoder), nil
+}
+
+func configurePrinterWriter(format yqlib.PrinterOutputFormat, out io.Writer, variables map[string]*list.List) (yqlib.PrinterWriter, error) {
 
 	var printerWriter yqlib.PrinterWriter
 
@@ -88,7 +191,7 @@ func configurePrinterWriter(format yqlib.PrinterOutputFormat, out io.Writer) (yq
 		if err != nil {
 			return nil, fmt.Errorf("bad split document expression: %w", err)
 		}
-		printerWriter = yqlib.NewMultiPrinterWriter(splitExp, format)
+		printerWriter = yqlib.NewMultiPrinterWriter(splitExp, format, variables)
 	} else {
 		printerWriter = yqlib.NewSinglePrinterWriter(out)
 	}
@@ -157,16 +260,29 @@ func processStdInArgs(args []string) []string {
 	return append(args, "-")
 }
 
-func processArgs(originalArgs []string) (string, []string, error) {
+func processArgs(originalArgs []string) (string, []string, []string, error) {
 	expression := forceExpression
 	if expressionFile != "" {
 		expressionBytes, err := os.ReadFile(expressionFile)
 		if err != nil {
-			return "", nil, err
+			return "", nil, nil, err
 		}
 		expression = string(expressionBytes)
 	}
 
+	if positionalArguments {
+		// everything after the expression is a positional argument, so read from stdin
+		positional := originalArgs
+		if expression == "" && len(positional) > 0 {
+			expression = positional[0]
+			positional = positional[1:]
+		}
+		if nullInput {
+			return expression, []string{}, positional, nil
+		}
+		return expression, []string{"-"}, positional, nil
+	}
+
 	args := processStdInArgs(originalArgs)
 	yqlib.GetLogger().Debugf("processed args: %v", args)
 	if expression == "" && len(args) > 0 && args[0] != "-" && !maybeFile(args[0]) {
@@ -174,5 +290,5 @@ func processArgs(originalArgs []string) (string, []string, error) {
 		expression = args[0]
 		args = args[1:]
 	}
-	return expression, args, nil
+	return expression, args, []string{}, nil
 }
@@ -15,10 +15,14 @@ type Evaluator interface {
 
 	// EvaluateCandidateNodes takes an expression and list of candidate nodes, returning a list of matching candidate nodes
 	EvaluateCandidateNodes(expression string, inputCandidateNodes *list.List) (*list.List, error)
+
+	// SetVariables sets variables (e.g. from command line arguments) that the expression can use as $name
+	SetVariables(variables map[string]*list.List)
 }
 
 type allAtOnceEvaluator struct {
 	treeNavigator DataTreeNavigator
+	variables     map[string]*list.List
 }
 
 func NewAllAtOnceEvaluator() Evaluator {
@@ -26,6 +30,10 @@ func NewAllAtOnceEvaluator() Evaluator {
 	return &allAtOnceEvaluator{treeNavigator: NewDataTreeNavigator()}
 }
 
+func (e *allAtOnceEvaluator) SetVariables(variables map[string]*list.List) {
+	e.variables = variables
+}
+
 func (e *allAtOnceEvaluator) EvaluateNodes(expression string, nodes ...*yaml.Node) (*list.List, error) {
 	inputCandidates := list.New()
 	for _, node := range nodes {
@@ -39,7 +47,7 @@ func (e *allAtOnceEvaluator) EvaluateCandidateNodes(expression string, inputCand
 	if err != nil {
 		return nil, err
 	}
-	context, err := e.treeNavigator.GetMatchingNodes(Context{MatchingNodes: inputCandidates}, node)
+	context, err := e.treeNavigator.GetMatchingNodes(newEvaluationContext(inputCandidates, e.variables), node)
 	if err != nil {
 		return nil, err
 	}
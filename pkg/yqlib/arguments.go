@@ -0,0 +1,116 @@
+package yqlib
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"io"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// newEvaluationContext creates the context an expression is first evaluated against, with
+// the variables given to the evaluator (e.g. the command line arguments) set on it.
+func newEvaluationContext(matchingNodes *list.List, variables map[string]*list.List) Context {
+	context := Context{MatchingNodes: matchingNodes}
+	for name, value := range variables {
+		// each evaluation gets its own copy, so updating a variable in one document
+		// does not carry over into the next
+		clone := list.New()
+		for el := value.Front(); el != nil; el = el.Next() {
+			clone.PushBack(&CandidateNode{Node: deepClone(el.Value.(*CandidateNode).Node)})
+		}
+		context.SetVariable(name, clone)
+	}
+	return context
+}
+
+type namedArgument struct {
+	name string
+	node *yaml.Node
+}
+
+// CommandLineArguments collects named (e.g. --arg name=value) and positional arguments,
+// and configures them as variables - as well as $ARGS, a map of all of them.
+type CommandLineArguments struct {
+	named      []namedArgument
+	positional []string
+}
+
+func NewCommandLineArguments() *CommandLineArguments {
+	return &CommandLineArguments{}
+}
+
+// AddString adds a named argument with a string value.
+func (a *CommandLineArguments) AddString(name string, value string) {
+	a.named = append(a.named, namedArgument{name: name, node: createStringScalarNode(value)})
+}
+
+// AddDecoded adds a named argument, decoding its value with the given decoder. When slurp is true
+// the value is an array of all the documents read, otherwise it must be a single document.
+func (a *CommandLineArguments) AddDecoded(name string, reader io.Reader, decoder Decoder, slurp bool) error {
+	err := decoder.Init(reader)
+	if err != nil {
+		return err
+	}
+
+	documents := make([]*yaml.Node, 0)
+	for {
+		candidate, err := decoder.Decode()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("could not decode the value of $%v: %w", name, err)
+		}
+		documents = append(documents, unwrapDoc(candidate.Node))
+	}
+
+	var node *yaml.Node
+	if slurp {
+		node = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: documents}
+	} else if len(documents) != 1 {
+		return fmt.Errorf("expected a single value for $%v, but got %v documents", name, len(documents))
+	} else {
+		node = documents[0]
+	}
+
+	a.named = append(a.named, namedArgument{name: name, node: node})
+	return nil
+}
+
+// AddPositional adds positional arguments, these are only available in $ARGS.positional.
+func (a *CommandLineArguments) AddPositional(values ...string) {
+	a.positional = append(a.positional, values...)
+}
+
+// Variables returns the named arguments as variables, as well as $ARGS - these can be given to
+// the evaluators with SetVariables.
+func (a *CommandLineArguments) Variables() map[string]*list.List {
+	variables := map[string]*list.List{}
+
+	// when a name is given more than once, the last value wins
+	named := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	namedIndexes := map[string]int{}
+	for _, argument := range a.named {
+		variables[argument.name] = (&CandidateNode{Node: argument.node}).AsList()
+		if index, exists := namedIndexes[argument.name]; exists {
+			named.Content[index+1] = argument.node
+			continue
+		}
+		namedIndexes[argument.name] = len(named.Content)
+		named.Content = append(named.Content, createStringScalarNode(argument.name), argument.node)
+	}
+
+	positional := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, value := range a.positional {
+		positional.Content = append(positional.Content, createStringScalarNode(value))
+	}
+
+	args := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+		createStringScalarNode("positional"), positional,
+		createStringScalarNode("named"), named,
+	}}
+	variables["ARGS"] = (&CandidateNode{Node: args}).AsList()
+
+	return variables
+}
@@ -0,0 +1,116 @@
+package yqlib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mikefarah/yq/v4/test"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestCommandLineArguments_Configure(t *testing.T) {
+	arguments := NewCommandLineArguments()
+	arguments.AddString("name", "cat")
+	err := arguments.AddDecoded("config", strings.NewReader(`{"a": [1, 2]}`), NewJSONDecoder(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	arguments.AddPositional("dog", "frog")
+
+	encoder := NewJSONEncoder(0, false, false)
+	decoder := NewYamlDecoder(ConfiguredYamlPreferences)
+
+	evaluator := NewStringEvaluator()
+	evaluator.SetVariables(arguments.Variables())
+	result, err := evaluator.Evaluate(`[$name, $config.a[1], $ARGS]`, "null", encoder, decoder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.AssertResult(t, `["cat",2,{"positional":["dog","frog"],"named":{"name":"cat","config":{"a":[1,2]}}}]`+"\n", result)
+}
+
+func TestCommandLineArguments_RepeatedNameLastWins(t *testing.T) {
+	arguments := NewCommandLineArguments()
+	arguments.AddString("a", "1")
+	arguments.AddString("b", "cat")
+	arguments.AddString("a", "2")
+
+	encoder := NewJSONEncoder(0, false, false)
+	decoder := NewYamlDecoder(ConfiguredYamlPreferences)
+
+	evaluator := NewStringEvaluator()
+	evaluator.SetVariables(arguments.Variables())
+	result, err := evaluator.Evaluate(`[$a, $ARGS.named]`, "null", encoder, decoder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.AssertResult(t, `["2",{"a":"2","b":"cat"}]`+"\n", result)
+}
+
+func TestCommandLineArguments_VariablesAreCopiedForEachDocument(t *testing.T) {
+	arguments := NewCommandLineArguments()
+	err := arguments.AddDecoded("x", strings.NewReader(`{"n": 0}`), NewJSONDecoder(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoder := NewJSONEncoder(0, false, false)
+	decoder := NewYamlDecoder(ConfiguredYamlPreferences)
+
+	evaluator := NewStringEvaluator()
+	evaluator.SetVariables(arguments.Variables())
+	result, err := evaluator.Evaluate(`($x.n += .a) | $x`, "a: 1\n---\na: 2\n", encoder, decoder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.AssertResult(t, `{"n":1}`+"\n"+`{"n":2}`+"\n", result)
+}
+
+func TestCommandLineArguments_EvaluatorsHaveTheirOwnVariables(t *testing.T) {
+	cats := NewCommandLineArguments()
+	cats.AddString("name", "cat")
+	dogs := NewCommandLineArguments()
+	dogs.AddString("name", "dog")
+
+	catEvaluator := NewAllAtOnceEvaluator()
+	catEvaluator.SetVariables(cats.Variables())
+	dogEvaluator := NewAllAtOnceEvaluator()
+	dogEvaluator.SetVariables(dogs.Variables())
+	plainEvaluator := NewAllAtOnceEvaluator()
+
+	for _, scenario := range []struct {
+		evaluator Evaluator
+		expected  string
+	}{
+		{catEvaluator, "cat"},
+		{dogEvaluator, "dog"},
+		{plainEvaluator, "null"},
+	} {
+		result, err := scenario.evaluator.EvaluateNodes(`$name // "null"`, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		test.AssertResult(t, scenario.expected, result.Front().Value.(*CandidateNode).Node.Value)
+	}
+}
+
+func TestCommandLineArguments_AddDecodedSlurp(t *testing.T) {
+	arguments := NewCommandLineArguments()
+	err := arguments.AddDecoded("docs", strings.NewReader("a: 1\n---\nb: 2\n"), NewYamlDecoder(LoadYamlPreferences), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.AssertResult(t, 2, len(arguments.named[0].node.Content))
+}
+
+func TestCommandLineArguments_AddDecodedExpectsOneDocument(t *testing.T) {
+	arguments := NewCommandLineArguments()
+	err := arguments.AddDecoded("docs", strings.NewReader("a: 1\n---\nb: 2\n"), NewYamlDecoder(LoadYamlPreferences), false)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	test.AssertResult(t, "expected a single value for $docs, but got 2 documents", err.Error())
+}
@@ -14,6 +14,7 @@ type Context struct {
 	Variables      map[string]*list.List
 	DontAutoCreate bool
 	datetimeLayout string
+	functions      map[string]*functionDefinition
 }
 
 func (n *Context) SingleReadonlyChildContext(candidate *CandidateNode) Context {
@@ -55,8 +56,26 @@ func (n *Context) SetVariable(name string, value *list.List) {
 	n.Variables[name] = value
 }
 
+func (n *Context) getFunction(name string, arity int) *functionDefinition {
+	if n.functions == nil {
+		return nil
+	}
+	return n.functions[functionKey(name, arity)]
+}
+
+// functions are never modified in place, as function definitions
+// capture the functions in scope when they were defined.
+func (n *Context) setFunction(name string, arity int, definition *functionDefinition) {
+	functions := make(map[string]*functionDefinition, len(n.functions)+1)
+	for key, existing := range n.functions {
+		functions[key] = existing
+	}
+	functions[functionKey(name, arity)] = definition
+	n.functions = functions
+}
+
 func (n *Context) ChildContext(results *list.List) Context {
-	clone := Context{DontAutoCreate: n.DontAutoCreate, datetimeLayout: n.datetimeLayout}
+	clone := Context{DontAutoCreate: n.DontAutoCreate, datetimeLayout: n.datetimeLayout, functions: n.functions}
 	clone.Variables = make(map[string]*list.List)
 	if len(n.Variables) > 0 {
 		err := copier.Copy(&clone.Variables, n.Variables)
@@ -80,6 +99,7 @@ func (n *Context) ToString() string {
 func (n *Context) DeepClone() Context {
 	clone := Context{}
 	err := copier.Copy(&clone, n)
+	clone.functions = n.functions
 	// copier doesn't do lists properly for some reason
 	clone.MatchingNodes = list.New()
 	for el := n.MatchingNodes.Front(); el != nil; el = el.Next() {
@@ -106,6 +126,8 @@ func (n *Context) Clone() Context {
 		log.Error("Error cloning context :(")
 		panic(err)
 	}
+	// copier skips unexported fields
+	clone.functions = n.functions
 	return clone
 }
 
@@ -15,6 +15,10 @@ const (
 	JsonInputFormat
 	CSVObjectInputFormat
 	TSVObjectInputFormat
+	Base32InputFormat
+	HexInputFormat
+	GzipInputFormat
+	ZlibInputFormat
 )
 
 type Decoder interface {
@@ -0,0 +1,67 @@
+package yqlib
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+type compressionDecoder struct {
+	reader    io.Reader
+	finished  bool
+	name      string
+	newReader func(io.Reader) (io.ReadCloser, error)
+}
+
+func NewGzipDecoder() Decoder {
+	return &compressionDecoder{finished: false, name: "gzip", newReader: func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) }}
+}
+
+func NewZlibDecoder() Decoder {
+	return &compressionDecoder{finished: false, name: "zlib", newReader: zlib.NewReader}
+}
+
+func (dec *compressionDecoder) Init(reader io.Reader) error {
+	dec.reader = reader
+	dec.finished = false
+	return nil
+}
+
+func (dec *compressionDecoder) Decode() (*CandidateNode, error) {
+	if dec.finished {
+		return nil, io.EOF
+	}
+	dec.finished = true
+
+	compressed := new(bytes.Buffer)
+	if _, err := compressed.ReadFrom(dec.reader); err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	// decompressing an empty string gives an empty string
+	if compressed.Len() > 0 {
+		decompressor, err := dec.newReader(compressed)
+		if err != nil {
+			return nil, fmt.Errorf("could not decompress %v data: %w", dec.name, err)
+		}
+		if _, err := buf.ReadFrom(decompressor); err != nil {
+			return nil, fmt.Errorf("could not decompress %v data: %w", dec.name, err)
+		}
+		if err := decompressor.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &CandidateNode{
+		Node: &yaml.Node{
+			Kind:  yaml.ScalarNode,
+			Tag:   "!!str",
+			Value: buf.String(),
+		},
+	}, nil
+}
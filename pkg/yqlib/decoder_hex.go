@@ -0,0 +1,56 @@
+package yqlib
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+type hexDecoder struct {
+	reader       io.Reader
+	finished     bool
+	readAnything bool
+}
+
+func NewHexDecoder() Decoder {
+	return &hexDecoder{finished: false}
+}
+
+func (dec *hexDecoder) Init(reader io.Reader) error {
+	dec.reader = reader
+	dec.readAnything = false
+	dec.finished = false
+	return nil
+}
+
+func (dec *hexDecoder) Decode() (*CandidateNode, error) {
+	if dec.finished {
+		return nil, io.EOF
+	}
+	hexReader := hex.NewDecoder(dec.reader)
+	buf := new(bytes.Buffer)
+
+	if _, err := buf.ReadFrom(hexReader); err != nil {
+		return nil, err
+	}
+	if buf.Len() == 0 {
+		dec.finished = true
+
+		// if we've read _only_ an empty string, lets return that
+		// otherwise if we've already read some bytes, and now we get
+		// an empty string, then we are done.
+		if dec.readAnything {
+			return nil, io.EOF
+		}
+	}
+	dec.readAnything = true
+	return &CandidateNode{
+		Node: &yaml.Node{
+			Kind:  yaml.ScalarNode,
+			Tag:   "!!str",
+			Value: buf.String(),
+		},
+	}, nil
+}
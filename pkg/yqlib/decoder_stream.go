@@ -0,0 +1,193 @@
+package yqlib
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/goccy/go-json"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// streamDecoder wraps another decoder, returning each of the documents it decodes
+// as a sequence of [path, leaf] and [path] stream events (see tostream).
+type streamDecoder struct {
+	decoder Decoder
+	events  *list.List
+}
+
+func NewStreamDecoder(decoder Decoder) Decoder {
+	return &streamDecoder{decoder: decoder}
+}
+
+func (dec *streamDecoder) Init(reader io.Reader) error {
+	dec.events = list.New()
+	return dec.decoder.Init(reader)
+}
+
+func (dec *streamDecoder) Decode() (*CandidateNode, error) {
+	for dec.events.Len() == 0 {
+		candidate, err := dec.decoder.Decode()
+		if err != nil {
+			return nil, err
+		}
+		streamEvents([]interface{}{}, candidate.Node, func(event *yaml.Node) {
+			dec.events.PushBack(event)
+		})
+	}
+	event := dec.events.Remove(dec.events.Front()).(*yaml.Node)
+	return &CandidateNode{Node: event}, nil
+}
+
+type jsonStreamFrame struct {
+	isMap        bool
+	key          interface{}
+	expectingKey bool
+}
+
+// jsonStreamDecoder reads json token by token, returning stream events as it
+// goes, so that the whole document never needs to be held in memory.
+type jsonStreamDecoder struct {
+	decoder *json.Decoder
+	frames  []*jsonStreamFrame
+	events  *list.List
+}
+
+func NewJSONStreamDecoder() Decoder {
+	return &jsonStreamDecoder{}
+}
+
+func (dec *jsonStreamDecoder) Init(reader io.Reader) error {
+	dec.decoder = json.NewDecoder(reader)
+	dec.decoder.UseNumber()
+	dec.frames = nil
+	dec.events = list.New()
+	return nil
+}
+
+func (dec *jsonStreamDecoder) Decode() (*CandidateNode, error) {
+	for dec.events.Len() == 0 {
+		if err := dec.readToken(); err != nil {
+			return nil, err
+		}
+	}
+	event := dec.events.Remove(dec.events.Front()).(*yaml.Node)
+	return &CandidateNode{Node: event}, nil
+}
+
+func (dec *jsonStreamDecoder) path() []interface{} {
+	path := make([]interface{}, len(dec.frames))
+	for i, frame := range dec.frames {
+		path[i] = frame.key
+	}
+	return path
+}
+
+func (dec *jsonStreamDecoder) currentFrame() *jsonStreamFrame {
+	if len(dec.frames) == 0 {
+		return nil
+	}
+	return dec.frames[len(dec.frames)-1]
+}
+
+func (dec *jsonStreamDecoder) startValue() {
+	if frame := dec.currentFrame(); frame != nil && !frame.isMap {
+		frame.key = frame.key.(int) + 1
+	}
+}
+
+func (dec *jsonStreamDecoder) endValue() {
+	if frame := dec.currentFrame(); frame != nil && frame.isMap {
+		frame.expectingKey = true
+	}
+}
+
+func (dec *jsonStreamDecoder) readToken() error {
+	token, err := dec.decoder.Token()
+	if errors.Is(err, io.EOF) && len(dec.frames) > 0 {
+		return io.ErrUnexpectedEOF
+	} else if err != nil {
+		return err
+	}
+
+	if frame := dec.currentFrame(); frame != nil && frame.isMap && frame.expectingKey {
+		switch token := token.(type) {
+		case string:
+			frame.key = token
+			frame.expectingKey = false
+			return nil
+		case json.Delim:
+			if token == '}' {
+				return dec.closeContainer()
+			}
+		}
+		return fmt.Errorf("expected a map key but got %v", token)
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		switch token {
+		case '{', '[':
+			return dec.openContainer(token == '{')
+		default:
+			return dec.closeContainer()
+		}
+	default:
+		dec.startValue()
+		scalar, err := dec.createScalar(token)
+		if err != nil {
+			return err
+		}
+		dec.events.PushBack(createStreamEvent(dec.path(), scalar))
+		dec.endValue()
+	}
+	return nil
+}
+
+func (dec *jsonStreamDecoder) openContainer(isMap bool) error {
+	dec.startValue()
+	if !dec.decoder.More() {
+		// empty map or array, consume the closing delimiter
+		if _, err := dec.decoder.Token(); err != nil {
+			return err
+		}
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if isMap {
+			node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		dec.events.PushBack(createStreamEvent(dec.path(), node))
+		dec.endValue()
+		return nil
+	}
+	frame := &jsonStreamFrame{isMap: isMap, key: -1, expectingKey: isMap}
+	dec.frames = append(dec.frames, frame)
+	return nil
+}
+
+func (dec *jsonStreamDecoder) closeContainer() error {
+	frame := dec.currentFrame()
+	if frame == nil {
+		return fmt.Errorf("unexpected closing delimiter")
+	}
+	dec.frames = dec.frames[:len(dec.frames)-1]
+	dec.events.PushBack(createStreamEvent(appendPath(dec.path(), frame.key), nil))
+	dec.endValue()
+	return nil
+}
+
+func (dec *jsonStreamDecoder) createScalar(token json.Token) (*yaml.Node, error) {
+	switch token := token.(type) {
+	case nil:
+		return createScalarNode(nil, "null"), nil
+	case json.Number:
+		if strings.ContainsAny(string(token), ".eE") {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: string(token)}, nil
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: string(token)}, nil
+	case string, bool:
+		return createScalarNode(token, fmt.Sprintf("%v", token)), nil
+	}
+	return nil, fmt.Errorf("unrecognised json token %v", token)
+}
@@ -0,0 +1,38 @@
+package yqlib
+
+import (
+	"encoding/base32"
+	"fmt"
+	"io"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+type base32Encoder struct {
+	encoding base32.Encoding
+}
+
+func NewBase32Encoder() Encoder {
+	return &base32Encoder{encoding: *base32.StdEncoding}
+}
+
+func (e *base32Encoder) CanHandleAliases() bool {
+	return false
+}
+
+func (e *base32Encoder) PrintDocumentSeparator(writer io.Writer) error {
+	return nil
+}
+
+func (e *base32Encoder) PrintLeadingContent(writer io.Writer, content string) error {
+	return nil
+}
+
+func (e *base32Encoder) Encode(writer io.Writer, originalNode *yaml.Node) error {
+	node := unwrapDoc(originalNode)
+	if guessTagFromCustomType(node) != "!!str" {
+		return fmt.Errorf("cannot encode %v as base32, can only operate on strings. Please first pipe through another encoding operator to convert the value to a string", node.Tag)
+	}
+	_, err := writer.Write([]byte(e.encoding.EncodeToString([]byte(node.Value))))
+	return err
+}
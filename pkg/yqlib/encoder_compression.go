@@ -0,0 +1,47 @@
+package yqlib
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+type compressionEncoder struct {
+	name      string
+	newWriter func(io.Writer) io.WriteCloser
+}
+
+func NewGzipEncoder() Encoder {
+	return &compressionEncoder{name: "gzip", newWriter: func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }}
+}
+
+func NewZlibEncoder() Encoder {
+	return &compressionEncoder{name: "zlib", newWriter: func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }}
+}
+
+func (e *compressionEncoder) CanHandleAliases() bool {
+	return false
+}
+
+func (e *compressionEncoder) PrintDocumentSeparator(writer io.Writer) error {
+	return nil
+}
+
+func (e *compressionEncoder) PrintLeadingContent(writer io.Writer, content string) error {
+	return nil
+}
+
+func (e *compressionEncoder) Encode(writer io.Writer, originalNode *yaml.Node) error {
+	node := unwrapDoc(originalNode)
+	if guessTagFromCustomType(node) != "!!str" {
+		return fmt.Errorf("cannot compress %v with %v, can only operate on strings. Please first pipe through another encoding operator to convert the value to a string", node.Tag, e.name)
+	}
+	compressor := e.newWriter(writer)
+	if _, err := compressor.Write([]byte(node.Value)); err != nil {
+		return err
+	}
+	return compressor.Close()
+}
@@ -0,0 +1,86 @@
+package yqlib
+
+import (
+	"crypto/md5"  // #nosec
+	"crypto/sha1" // #nosec
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"io"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+type hashEncoder struct {
+	newHash func() hash.Hash
+}
+
+func NewMD5Encoder() Encoder {
+	return &hashEncoder{newHash: md5.New}
+}
+
+func NewSHA1Encoder() Encoder {
+	return &hashEncoder{newHash: sha1.New}
+}
+
+func NewSHA256Encoder() Encoder {
+	return &hashEncoder{newHash: sha256.New}
+}
+
+func NewSHA512Encoder() Encoder {
+	return &hashEncoder{newHash: sha512.New}
+}
+
+func (e *hashEncoder) CanHandleAliases() bool {
+	return false
+}
+
+func (e *hashEncoder) PrintDocumentSeparator(writer io.Writer) error {
+	return nil
+}
+
+func (e *hashEncoder) PrintLeadingContent(writer io.Writer, content string) error {
+	return nil
+}
+
+func (e *hashEncoder) Encode(writer io.Writer, originalNode *yaml.Node) error {
+	node := unwrapDoc(originalNode)
+	value := node.Value
+	if node.Kind != yaml.ScalarNode {
+		var err error
+		value, err = canonicalEncoding(node)
+		if err != nil {
+			return err
+		}
+	}
+	hasher := e.newHash()
+	if _, err := hasher.Write([]byte(value)); err != nil {
+		return err
+	}
+	_, err := writer.Write([]byte(hex.EncodeToString(hasher.Sum(nil))))
+	return err
+}
+
+// canonicalEncoding encodes maps and arrays as compact json with all keys sorted,
+// so that the same data always gives the same string regardless of its
+// key order, comments and styles.
+func canonicalEncoding(node *yaml.Node) (string, error) {
+	clone := deepClone(node)
+	sortKeysRecursively(clone)
+	value, err := encodeToString(&CandidateNode{Node: clone}, encoderPreferences{format: JSONOutputFormat, indent: 0})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(value, "\n"), nil
+}
+
+func sortKeysRecursively(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		sortKeys(node)
+	}
+	for _, child := range node.Content {
+		sortKeysRecursively(child)
+	}
+}
@@ -0,0 +1,37 @@
+package yqlib
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+type hexEncoder struct {
+}
+
+func NewHexEncoder() Encoder {
+	return &hexEncoder{}
+}
+
+func (e *hexEncoder) CanHandleAliases() bool {
+	return false
+}
+
+func (e *hexEncoder) PrintDocumentSeparator(writer io.Writer) error {
+	return nil
+}
+
+func (e *hexEncoder) PrintLeadingContent(writer io.Writer, content string) error {
+	return nil
+}
+
+func (e *hexEncoder) Encode(writer io.Writer, originalNode *yaml.Node) error {
+	node := unwrapDoc(originalNode)
+	if guessTagFromCustomType(node) != "!!str" {
+		return fmt.Errorf("cannot encode %v as hex, can only operate on strings. Please first pipe through another encoding operator to convert the value to a string", node.Tag)
+	}
+	_, err := writer.Write([]byte(hex.EncodeToString([]byte(node.Value))))
+	return err
+}
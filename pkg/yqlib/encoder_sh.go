@@ -0,0 +1,69 @@
+package yqlib
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+type shEncoder struct {
+}
+
+func NewShEncoder() Encoder {
+	return &shEncoder{}
+}
+
+func (e *shEncoder) CanHandleAliases() bool {
+	return false
+}
+
+func (e *shEncoder) PrintDocumentSeparator(writer io.Writer) error {
+	return nil
+}
+
+func (e *shEncoder) PrintLeadingContent(writer io.Writer, content string) error {
+	return nil
+}
+
+func (e *shEncoder) Encode(writer io.Writer, originalNode *yaml.Node) error {
+	node := unwrapDoc(originalNode)
+	var values []string
+	switch node.Kind {
+	case yaml.ScalarNode, yaml.AliasNode:
+		value, err := e.quote(node)
+		if err != nil {
+			return err
+		}
+		values = append(values, value)
+	case yaml.SequenceNode:
+		for _, child := range node.Content {
+			value, err := e.quote(child)
+			if err != nil {
+				return err
+			}
+			values = append(values, value)
+		}
+	default:
+		return fmt.Errorf("cannot encode %v as sh, can only operate on scalars and arrays of scalars", node.Tag)
+	}
+	_, err := writer.Write([]byte(strings.Join(values, " ")))
+	return err
+}
+
+// quote wraps strings in single quotes, so they are safe to use as a single shell word.
+// Other scalars (numbers, booleans, null) are printed as is.
+func (e *shEncoder) quote(node *yaml.Node) (string, error) {
+	node = resolveAlias(node)
+	if node.Kind != yaml.ScalarNode {
+		return "", fmt.Errorf("cannot encode %v as sh, can only operate on scalars and arrays of scalars", node.Tag)
+	}
+	switch guessTagFromCustomType(node) {
+	case "!!str":
+		return "'" + strings.ReplaceAll(node.Value, "'", `'\''`) + "'", nil
+	case "!!null":
+		return "null", nil
+	}
+	return node.Value, nil
+}
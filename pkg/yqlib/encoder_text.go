@@ -0,0 +1,71 @@
+package yqlib
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// textEncoder converts the node to a string (like to_string) and then
+// escapes it for the target format.
+type textEncoder struct {
+	escape func(string) string
+}
+
+var htmlEscaper = strings.NewReplacer(
+	"<", "&lt;",
+	">", "&gt;",
+	"&", "&amp;",
+	"'", "&#39;",
+	"\"", "&quot;",
+)
+
+func NewTextEncoder() Encoder {
+	return &textEncoder{escape: func(value string) string { return value }}
+}
+
+func NewHTMLEncoder() Encoder {
+	return &textEncoder{escape: htmlEscaper.Replace}
+}
+
+func NewURIEncoder() Encoder {
+	return &textEncoder{escape: uriEscape}
+}
+
+// uriEscape percent-encodes everything but the unreserved characters of rfc3986.
+func uriEscape(value string) string {
+	var sb strings.Builder
+	for _, b := range []byte(value) {
+		if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') ||
+			b == '-' || b == '_' || b == '.' || b == '~' {
+			sb.WriteByte(b)
+		} else {
+			sb.WriteString(fmt.Sprintf("%%%02X", b))
+		}
+	}
+	return sb.String()
+}
+
+func (e *textEncoder) CanHandleAliases() bool {
+	return false
+}
+
+func (e *textEncoder) PrintDocumentSeparator(writer io.Writer) error {
+	return nil
+}
+
+func (e *textEncoder) PrintLeadingContent(writer io.Writer, content string) error {
+	return nil
+}
+
+func (e *textEncoder) Encode(writer io.Writer, originalNode *yaml.Node) error {
+	node := unwrapDoc(originalNode)
+	textNode, err := toString(&CandidateNode{Node: node}, node)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write([]byte(e.escape(textNode.Value)))
+	return err
+}
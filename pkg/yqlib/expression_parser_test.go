@@ -45,6 +45,41 @@ func TestParserNoMatchingCloseBracketInCollectObject(t *testing.T) {
 	test.AssertResultComplex(t, "bad expression, could not find matching `)`", err.Error())
 }
 
+func TestParserThenWithoutIf(t *testing.T) {
+	_, err := getExpressionParser().ParseExpression(`then 1`)
+	test.AssertResultComplex(t, "bad expression, 'then' without matching 'if'", err.Error())
+}
+
+func TestParserNoMatchingEndForIf(t *testing.T) {
+	_, err := getExpressionParser().ParseExpression(`if . then 1`)
+	test.AssertResultComplex(t, "bad expression, could not find matching 'end' for 'if'", err.Error())
+}
+
+func TestParserElseAfterElse(t *testing.T) {
+	_, err := getExpressionParser().ParseExpression(`if . then 1 else 2 else 3 end`)
+	test.AssertResultComplex(t, "bad expression, 'else' without matching 'if ... then'", err.Error())
+}
+
+func TestParserFunctionDefinitionWithoutSemicolon(t *testing.T) {
+	_, err := getExpressionParser().ParseExpression(`def f: 1`)
+	test.AssertResultComplex(t, "bad expression, function definition must be terminated with ';'", err.Error())
+}
+
+func TestParserFunctionDefinitionBadParam(t *testing.T) {
+	_, err := getExpressionParser().ParseExpression(`def f(a b): 1; f`)
+	test.AssertResultComplex(t, "bad parameter 'a b' in function definition 'def f(a b):'", err.Error())
+}
+
+func TestParserReduceWithoutArgs(t *testing.T) {
+	_, err := getExpressionParser().ParseExpression(`reduce .[] as $x`)
+	test.AssertResultComplex(t, "bad expression, reduce must be of the form reduce .[] as $x (<init>; <update>)", err.Error())
+}
+
+func TestParserForeachWithoutArgs(t *testing.T) {
+	_, err := getExpressionParser().ParseExpression(`foreach .[] as $x | .`)
+	test.AssertResultComplex(t, "bad expression, foreach must be given arguments e.g. foreach .[] as $x (<init>; <update>)", err.Error())
+}
+
 func TestParserNoArgsForTwoArgOp(t *testing.T) {
 	_, err := getExpressionParser().ParseExpression("=")
 	test.AssertResultComplex(t, "'=' expects 2 args but there is 0", err.Error())
@@ -66,8 +101,8 @@ func TestParserTwoArgsForTwoArgOp(t *testing.T) {
 }
 
 func TestParserNoArgsForOneArgOp(t *testing.T) {
-	_, err := getExpressionParser().ParseExpression("explode")
-	test.AssertResultComplex(t, "'explode' expects 1 arg but received none", err.Error())
+	_, err := getExpressionParser().ParseExpression("select")
+	test.AssertResultComplex(t, "'select' expects 1 arg but received none", err.Error())
 }
 
 func TestParserOneArgForOneArgOp(t *testing.T) {
@@ -79,3 +114,16 @@ func TestParserExtraArgs(t *testing.T) {
 	_, err := getExpressionParser().ParseExpression("sortKeys(.) explode(.)")
 	test.AssertResultComplex(t, "bad expression, please check expression syntax", err.Error())
 }
+
+func TestParserStringInterpolationNoMatchingCloseBracket(t *testing.T) {
+	// left as text, e.g. for regular expressions
+	node, err := getExpressionParser().ParseExpression(`"\(.a"`)
+	test.AssertResultComplex(t, nil, err)
+	test.AssertResultComplex(t, valueOpType, node.Operation.OperationType)
+}
+
+func TestParserStringInterpolationBadExpression(t *testing.T) {
+	node, err := getExpressionParser().ParseExpression(`"\(.a | )"`)
+	test.AssertResultComplex(t, nil, err)
+	test.AssertResultComplex(t, valueOpType, node.Operation.OperationType)
+}
@@ -117,6 +117,13 @@ func (p *expressionPostFixerImpl) ConvertToPostfix(infixTokens []*token) ([]*Ope
 				opStack[len(opStack)-1].Operation.OperationType.Precedence > currentPrecedence {
 				opStack, result = popOpToResult(opStack, result)
 			}
+			if currentToken.Operation.OperationType == optionalOpType {
+				// postfix operators (e.g. `(.a | error("x"))?`) apply to the expression
+				// that has just been processed, so they go straight to the result
+				result = append(result, currentToken.Operation)
+				log.Debugf("put %v onto the result", currentToken.toString(true))
+				continue
+			}
 			// add this operator to the opStack
 			opStack = append(opStack, currentToken)
 			log.Debugf("put %v onto the opstack", currentToken.toString(true))
@@ -205,6 +205,16 @@ var pathTests = []struct {
 		append(make([]interface{}, 0), "key", "SHORT_PIPE", "array", "MULTIPLY", "key", "SHORT_PIPE", "array2"),
 		append(make([]interface{}, 0), "key", "array", "SHORT_PIPE", "key", "array2", "SHORT_PIPE", "MULTIPLY"),
 	},
+	{
+		`.key.array / .key.array2`,
+		append(make([]interface{}, 0), "key", "SHORT_PIPE", "array", "DIVIDE", "key", "SHORT_PIPE", "array2"),
+		append(make([]interface{}, 0), "key", "array", "SHORT_PIPE", "key", "array2", "SHORT_PIPE", "DIVIDE"),
+	},
+	{
+		`.key.array % .key.array2`,
+		append(make([]interface{}, 0), "key", "SHORT_PIPE", "array", "MODULO", "key", "SHORT_PIPE", "array2"),
+		append(make([]interface{}, 0), "key", "array", "SHORT_PIPE", "key", "array2", "SHORT_PIPE", "MODULO"),
+	},
 	{
 		`.key.array // .key.array2`,
 		append(make([]interface{}, 0), "key", "SHORT_PIPE", "array", "ALTERNATIVE", "key", "SHORT_PIPE", "array2"),
@@ -265,6 +275,11 @@ var pathTests = []struct {
 		append(make([]interface{}, 0), "EXPLODE", "(", "a", "SHORT_PIPE", "b", ")"),
 		append(make([]interface{}, 0), "a", "b", "SHORT_PIPE", "EXPLODE"),
 	},
+	{
+		`.a | explode`,
+		append(make([]interface{}, 0), "a", "PIPE", "EXPLODE_STRING"),
+		append(make([]interface{}, 0), "a", "EXPLODE_STRING", "PIPE"),
+	},
 	{
 		`.a.b style="folded"`,
 		append(make([]interface{}, 0), "a", "SHORT_PIPE", "b", "ASSIGN_STYLE", "folded (string)"),
@@ -310,6 +325,31 @@ var pathTests = []struct {
 		append(make([]interface{}, 0), "foo*", "PIPE", "(", "SELF", "ASSIGN_STYLE", "flow (string)", ")"),
 		append(make([]interface{}, 0), "foo*", "SELF", "flow (string)", "ASSIGN_STYLE", "PIPE"),
 	},
+	{
+		`if .a then .b else .c end`,
+		append(make([]interface{}, 0), "(", "(", "a", ")", "IF", "(", "(", "b", ")", "BLOCK", "(", "c", ")", ")", ")"),
+		append(make([]interface{}, 0), "a", "b", "c", "BLOCK", "IF"),
+	},
+	{
+		`.a | def f(x): x + 1; f(2)`,
+		append(make([]interface{}, 0), "a", "PIPE", "(", "DEFINE_FUNCTION", "(", "CALL_FUNCTION", "ADD", "1 (int64)", ")", "BLOCK", "CALL_FUNCTION", "(", "2 (int64)", ")", ")"),
+		append(make([]interface{}, 0), "a", "CALL_FUNCTION", "1 (int64)", "ADD", "DEFINE_FUNCTION", "2 (int64)", "CALL_FUNCTION", "BLOCK", "PIPE"),
+	},
+	{
+		`(.a | .b)?.c`,
+		append(make([]interface{}, 0), "(", "a", "PIPE", "b", ")", "OPTIONAL", "SHORT_PIPE", "c"),
+		append(make([]interface{}, 0), "a", "b", "PIPE", "OPTIONAL", "c", "SHORT_PIPE"),
+	},
+	{
+		`try .a.b catch "x"`,
+		append(make([]interface{}, 0), "TRY", "a", "SHORT_PIPE", "b", "CATCH", "x (string)"),
+		append(make([]interface{}, 0), "a", "b", "SHORT_PIPE", "TRY", "x (string)", "CATCH"),
+	},
+	{
+		`reduce .[] as $x (0; . + $x)`,
+		append(make([]interface{}, 0), "(", "(", "SELF", "TRAVERSE_ARRAY", "[", "EMPTY", "]", "ASSIGN_VARIABLE", "GET_VARIABLE", ")", "REDUCE_EACH", "(", "0 (int64)", "BLOCK", "SELF", "ADD", "GET_VARIABLE", ")", ")"),
+		append(make([]interface{}, 0), "SELF", "EMPTY", "COLLECT", "TRAVERSE_ARRAY", "GET_VARIABLE", "ASSIGN_VARIABLE", "0 (int64)", "SELF", "GET_VARIABLE", "ADD", "BLOCK", "REDUCE_EACH"),
+	},
 }
 
 var tokeniser = newParticipleLexer()
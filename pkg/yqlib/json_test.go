@@ -228,6 +228,43 @@ var jsonScenarios = []formatScenario{
 		expected:     "- true\n- false\n",
 		scenarioType: "decode-ndjson",
 	},
+	{
+		description:  "stream json",
+		skipDoc:      true,
+		input:        `{"a": [1, {"b": 2.5}], "c": {}, "d": [], "e": null} "x" [true]`,
+		expected:     "[[\"a\",0],1]\n[[\"a\",1,\"b\"],2.5]\n[[\"a\",1,\"b\"]]\n[[\"a\",1]]\n[[\"c\"],{}]\n[[\"d\"],[]]\n[[\"e\"],null]\n[[\"e\"]]\n[[],\"x\"]\n[[0],true]\n[[0]]\n",
+		scenarioType: "decode-stream",
+	},
+	{
+		description:  "stream json then fromstream",
+		skipDoc:      true,
+		input:        `{"a": [1, {"b": 2}], "c": "cat"} {"d": 3}`,
+		expression:   `fromstream(.)`,
+		expected:     "{\"a\":[1,{\"b\":2}],\"c\":\"cat\"}\n{\"d\":3}\n",
+		scenarioType: "decode-stream",
+	},
+	{
+		description:  "stream json, filtering events",
+		skipDoc:      true,
+		input:        `{"a": [1, 2], "b": [3]}`,
+		expression:   `fromstream(. as $event | 1 | truncate_stream($event | select(.[0][0] == "b")))`,
+		expected:     "[3]\n",
+		scenarioType: "decode-stream",
+	},
+	{
+		description:   "stream bad json",
+		skipDoc:       true,
+		input:         `{"a": [1`,
+		expectedError: "bad file 'sample.yml': unexpected EOF",
+		scenarioType:  "decode-stream-error",
+	},
+	{
+		description:  "stream yaml",
+		skipDoc:      true,
+		input:        "a: &x [1]\nb: *x\n",
+		expected:     "[[\"a\",0],1]\n[[\"a\",0]]\n[[\"b\",0],1]\n[[\"b\",0]]\n[[\"b\"]]\n",
+		scenarioType: "decode-yaml-stream",
+	},
 }
 
 func documentRoundtripNdJsonScenario(w *bufio.Writer, s formatScenario, indent int) {
@@ -318,6 +355,17 @@ func testJSONScenario(t *testing.T, s formatScenario) {
 		test.AssertResultWithContext(t, s.expected, mustProcessFormatScenario(s, NewJSONDecoder(), NewJSONEncoder(0, false, false)), s.description)
 	case "roundtrip-multi":
 		test.AssertResultWithContext(t, s.expected, mustProcessFormatScenario(s, NewJSONDecoder(), NewJSONEncoder(2, false, false)), s.description)
+	case "decode-stream":
+		test.AssertResultWithContext(t, s.expected, mustProcessFormatScenario(s, NewJSONStreamDecoder(), NewJSONEncoder(0, false, false)), s.description)
+	case "decode-yaml-stream":
+		test.AssertResultWithContext(t, s.expected, mustProcessFormatScenario(s, NewStreamDecoder(NewYamlDecoder(ConfiguredYamlPreferences)), NewJSONEncoder(0, false, false)), s.description)
+	case "decode-stream-error":
+		result, err := processFormatScenario(s, NewJSONStreamDecoder(), NewJSONEncoder(0, false, false))
+		if err == nil {
+			t.Errorf("Expected error '%v' but it worked: %v", s.expectedError, result)
+		} else {
+			test.AssertResultComplexWithContext(t, s.expectedError, err.Error(), s.description)
+		}
 	case "decode-error":
 		result, err := processFormatScenario(s, NewJSONDecoder(), NewJSONEncoder(2, false, false))
 		if err == nil {
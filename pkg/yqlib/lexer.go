@@ -3,6 +3,7 @@ package yqlib
 import (
 	"fmt"
 	"regexp"
+	"strings"
 )
 
 type expressionTokeniser interface {
@@ -20,6 +21,13 @@ const (
 	openCollectObject
 	closeCollectObject
 	traverseArrayCollect
+	openIf
+	thenIf
+	elifIf
+	elseIf
+	closeIf
+	openReduce
+	openForeach
 )
 
 type token struct {
@@ -50,6 +58,20 @@ func (t *token) toString(detail bool) string {
 		return "}"
 	} else if t.TokenType == traverseArrayCollect {
 		return ".["
+	} else if t.TokenType == openIf {
+		return "if"
+	} else if t.TokenType == thenIf {
+		return "then"
+	} else if t.TokenType == elifIf {
+		return "elif"
+	} else if t.TokenType == elseIf {
+		return "else"
+	} else if t.TokenType == closeIf {
+		return "end"
+	} else if t.TokenType == openReduce {
+		return "reduce"
+	} else if t.TokenType == openForeach {
+		return "foreach"
 
 	} else {
 		return "NFI"
@@ -81,6 +103,240 @@ func hasOptionParameter(value string, option string) bool {
 	return len(optionParser.FindStringSubmatch(parameterString)) > 0
 }
 
+func bracketToken(tt tokenType) *token {
+	return &token{TokenType: tt}
+}
+
+func newOpToken(opType *operationType) *token {
+	return &token{TokenType: operationToken, Operation: &Operation{OperationType: opType, Value: opType.Type, StringValue: opType.Type}}
+}
+
+// expandConditionals rewrites 'if c then a elif c2 then b else d end' into
+// bracketed IF / BLOCK operations, so the postfixer doesn't need to know about them:
+// ((c) IF ((a) BLOCK ((c2) IF ((b) BLOCK (d)))))
+// A missing else branch defaults to '.', as it does in jq.
+func expandConditionals(tokens []*token) ([]*token, error) {
+	var expandedTokens = make([]*token, 0, len(tokens))
+
+	// for each open 'if', track how many 'elif's are nested within it,
+	// and whether we are expecting 'then' or have seen 'else'.
+	type ifState struct {
+		elifs      int
+		expectThen bool
+		seenElse   bool
+	}
+	var ifStack []*ifState
+
+	for _, currentToken := range tokens {
+		switch currentToken.TokenType {
+		case openIf:
+			ifStack = append(ifStack, &ifState{expectThen: true})
+			expandedTokens = append(expandedTokens, bracketToken(openBracket), bracketToken(openBracket))
+		case thenIf:
+			if len(ifStack) == 0 || !ifStack[len(ifStack)-1].expectThen {
+				return nil, fmt.Errorf("bad expression, 'then' without matching 'if'")
+			}
+			ifStack[len(ifStack)-1].expectThen = false
+			expandedTokens = append(expandedTokens, bracketToken(closeBracket), newOpToken(ifOpType), bracketToken(openBracket), bracketToken(openBracket))
+		case elifIf:
+			if len(ifStack) == 0 || ifStack[len(ifStack)-1].expectThen || ifStack[len(ifStack)-1].seenElse {
+				return nil, fmt.Errorf("bad expression, 'elif' without matching 'if ... then'")
+			}
+			state := ifStack[len(ifStack)-1]
+			state.elifs++
+			state.expectThen = true
+			expandedTokens = append(expandedTokens, bracketToken(closeBracket), newOpToken(blockOpType), bracketToken(openBracket), bracketToken(openBracket))
+		case elseIf:
+			if len(ifStack) == 0 || ifStack[len(ifStack)-1].expectThen || ifStack[len(ifStack)-1].seenElse {
+				return nil, fmt.Errorf("bad expression, 'else' without matching 'if ... then'")
+			}
+			ifStack[len(ifStack)-1].seenElse = true
+			expandedTokens = append(expandedTokens, bracketToken(closeBracket), newOpToken(blockOpType), bracketToken(openBracket))
+		case closeIf:
+			if len(ifStack) == 0 || ifStack[len(ifStack)-1].expectThen {
+				return nil, fmt.Errorf("bad expression, 'end' without matching 'if ... then'")
+			}
+			state := ifStack[len(ifStack)-1]
+			ifStack = ifStack[:len(ifStack)-1]
+			if !state.seenElse {
+				expandedTokens = append(expandedTokens, bracketToken(closeBracket), newOpToken(blockOpType), bracketToken(openBracket), newOpToken(selfReferenceOpType))
+			}
+			for i := 0; i < 2+2*state.elifs; i++ {
+				expandedTokens = append(expandedTokens, bracketToken(closeBracket))
+			}
+			expandedTokens = append(expandedTokens, &token{TokenType: closeBracket, CheckForPostTraverse: true})
+		default:
+			expandedTokens = append(expandedTokens, currentToken)
+		}
+	}
+	if len(ifStack) > 0 {
+		return nil, fmt.Errorf("bad expression, could not find matching 'end' for 'if'")
+	}
+	return expandedTokens, nil
+}
+
+// expandReduce rewrites the jq style 'reduce <exp> as $x (<init>; <update>)'
+// into the infix form: ((<exp> as $x) REDUCE (<init>; <update>))
+// and similarly for 'foreach <exp> as $x (<init>; <update>; <extract>)'
+// Like jq, these are evaluated separately for each input, unlike ireduce.
+func expandReduce(tokens []*token) ([]*token, error) {
+	var expandedTokens = make([]*token, 0, len(tokens))
+
+	const (
+		expectAs = iota
+		expectVariable
+		expectArgs
+		inArgs
+	)
+	type reduceState struct {
+		name   string
+		opType *operationType
+		depth  int
+		stage  int
+	}
+	var reduceStack []*reduceState
+	depth := 0
+
+	for _, currentToken := range tokens {
+		var state *reduceState
+		if len(reduceStack) > 0 {
+			state = reduceStack[len(reduceStack)-1]
+		}
+
+		if state != nil && state.stage == expectArgs {
+			if currentToken.TokenType != openBracket {
+				return nil, fmt.Errorf("bad expression, %v must be given arguments e.g. %v .[] as $x (<init>; <update>)", state.name, state.name)
+			}
+			state.stage = inArgs
+		}
+
+		switch {
+		case currentToken.TokenType == openReduce, currentToken.TokenType == openForeach:
+			state = &reduceState{name: currentToken.toString(false), opType: reduceEachOpType, stage: expectAs}
+			if currentToken.TokenType == openForeach {
+				state.opType = foreachOpType
+			}
+			expandedTokens = append(expandedTokens, bracketToken(openBracket), bracketToken(openBracket))
+			depth = depth + 2
+			state.depth = depth
+			reduceStack = append(reduceStack, state)
+			continue
+		case currentToken.TokenType == openBracket, currentToken.TokenType == openCollect,
+			currentToken.TokenType == openCollectObject, currentToken.TokenType == traverseArrayCollect:
+			depth++
+		case currentToken.TokenType == closeBracket, currentToken.TokenType == closeCollect,
+			currentToken.TokenType == closeCollectObject:
+			if state != nil && state.depth == depth && state.stage != inArgs {
+				return nil, fmt.Errorf("bad expression, %v must be of the form %v .[] as $x (<init>; <update>)", state.name, state.name)
+			}
+			depth--
+			if state != nil && state.stage == inArgs && state.depth == depth {
+				expandedTokens = append(expandedTokens, currentToken, bracketToken(closeBracket))
+				depth--
+				reduceStack = reduceStack[:len(reduceStack)-1]
+				continue
+			}
+		case state != nil && state.depth == depth && state.stage == expectAs && tokenIsOpType(currentToken, assignVariableOpType):
+			state.stage = expectVariable
+		case state != nil && state.depth == depth && state.stage == expectVariable && tokenIsOpType(currentToken, getVariableOpType):
+			expandedTokens = append(expandedTokens, currentToken, bracketToken(closeBracket), newOpToken(state.opType))
+			depth--
+			state.depth = depth
+			state.stage = expectArgs
+			continue
+		}
+		expandedTokens = append(expandedTokens, currentToken)
+	}
+	if len(reduceStack) > 0 {
+		state := reduceStack[len(reduceStack)-1]
+		return nil, fmt.Errorf("bad expression, %v must be of the form %v .[] as $x (<init>; <update>)", state.name, state.name)
+	}
+	return expandedTokens, nil
+}
+
+// expandFunctionDefinitions brackets function definitions, so that
+// 'a | def f: body; rest' is parsed as a | ((DEFINE_FUNCTION (body)) BLOCK rest)
+// The body of the function ends at the first ';' at the same level of nesting,
+// and the function is in scope until the end of the enclosing expression.
+// Like jq, user definitions (and their parameters) take precedence over builtins of the same name.
+func expandFunctionDefinitions(tokens []*token) ([]*token, error) {
+	var expandedTokens = make([]*token, 0, len(tokens))
+
+	type definitionState struct {
+		depth  int
+		inBody bool
+		name   string
+		params []string
+	}
+	var definitions []*definitionState
+	depth := 0
+
+	isDefined := func(name string, arity int) bool {
+		for _, definition := range definitions {
+			if definition.name == name && len(definition.params) == arity {
+				return true
+			}
+			if definition.inBody && arity == 0 {
+				for _, param := range definition.params {
+					if strings.TrimPrefix(param, "$") == name {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+
+	closeScopes := func() {
+		for len(definitions) > 0 && !definitions[len(definitions)-1].inBody && definitions[len(definitions)-1].depth == depth {
+			definitions = definitions[:len(definitions)-1]
+			expandedTokens = append(expandedTokens, bracketToken(closeBracket))
+			depth--
+		}
+	}
+	inBody := func() bool {
+		return len(definitions) > 0 && definitions[len(definitions)-1].inBody && definitions[len(definitions)-1].depth == depth
+	}
+
+	for index, currentToken := range tokens {
+		if isShadowableBuiltin(currentToken) && isDefined(currentToken.Operation.StringValue, builtinArity(tokens, index)) {
+			currentToken = callFunctionToken(currentToken.Operation.StringValue)
+		}
+		switch {
+		case currentToken.TokenType == openBracket, currentToken.TokenType == openCollect,
+			currentToken.TokenType == openCollectObject, currentToken.TokenType == traverseArrayCollect:
+			depth++
+		case currentToken.TokenType == closeBracket, currentToken.TokenType == closeCollect,
+			currentToken.TokenType == closeCollectObject:
+			closeScopes()
+			if inBody() {
+				return nil, fmt.Errorf("bad expression, function definition must be terminated with ';'")
+			}
+			depth--
+		case tokenIsOpType(currentToken, blockOpType):
+			closeScopes()
+			if inBody() {
+				expandedTokens = append(expandedTokens, bracketToken(closeBracket))
+				depth--
+				definitions[len(definitions)-1].inBody = false
+				definitions[len(definitions)-1].depth = depth
+			}
+		case tokenIsOpType(currentToken, defineFunctionOpType):
+			expandedTokens = append(expandedTokens, bracketToken(openBracket), currentToken, bracketToken(openBracket))
+			depth = depth + 2
+			prefs := currentToken.Operation.Preferences.(defineFunctionPreferences)
+			definitions = append(definitions, &definitionState{depth: depth, inBody: true, name: prefs.Name, params: prefs.Params})
+			continue
+		}
+		expandedTokens = append(expandedTokens, currentToken)
+	}
+	closeScopes()
+	if len(definitions) > 0 {
+		return nil, fmt.Errorf("bad expression, function definition must be terminated with ';'")
+	}
+	return expandedTokens, nil
+}
+
 func postProcessTokens(tokens []*token) []*token {
 	var postProcessedTokens = make([]*token, 0)
 
@@ -97,10 +353,75 @@ func postProcessTokens(tokens []*token) []*token {
 	return postProcessedTokens
 }
 
+var builtinNameParser = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z_0-9]*$`)
+
+// isShadowableBuiltin is true for builtins that are called like functions, e.g. add or splits(...)
+func isShadowableBuiltin(token *token) bool {
+	if token.TokenType != operationToken {
+		return false
+	}
+	opType := token.Operation.OperationType
+	switch opType {
+	case callFunctionOpType, defineFunctionOpType, valueOpType, getVariableOpType, traversePathOpType, tryOpType:
+		return false
+	}
+	// synthesised tokens are named after their operation type
+	return opType.NumArgs < 2 && token.Operation.StringValue != opType.Type &&
+		builtinNameParser.MatchString(token.Operation.StringValue)
+}
+
+// builtinArity counts the arguments passed to the builtin at index, e.g. f(a; b) has two
+func builtinArity(tokens []*token, index int) int {
+	if index+1 >= len(tokens) || tokens[index+1].TokenType != openBracket {
+		return 0
+	}
+	arity := 1
+	depth := 0
+	skipSeparators := 0
+	for _, currentToken := range tokens[index+1:] {
+		switch {
+		case currentToken.TokenType == openBracket, currentToken.TokenType == openCollect,
+			currentToken.TokenType == openCollectObject, currentToken.TokenType == traverseArrayCollect:
+			depth++
+		case currentToken.TokenType == closeBracket, currentToken.TokenType == closeCollect,
+			currentToken.TokenType == closeCollectObject:
+			depth--
+			if depth == 0 {
+				return arity
+			}
+		case depth == 1 && tokenIsOpType(currentToken, defineFunctionOpType):
+			// the ';' ending a function definition does not separate arguments
+			skipSeparators++
+		case depth == 1 && tokenIsOpType(currentToken, blockOpType):
+			if skipSeparators > 0 {
+				skipSeparators--
+			} else {
+				arity++
+			}
+		}
+	}
+	return arity
+}
+
+func callFunctionToken(name string) *token {
+	op := &Operation{OperationType: callFunctionOpType, Value: callFunctionOpType.Type, StringValue: name}
+	return &token{TokenType: operationToken, Operation: op, CheckForPostTraverse: true}
+}
+
 func tokenIsOpType(token *token, opType *operationType) bool {
 	return token.TokenType == operationToken && token.Operation.OperationType == opType
 }
 
+// operators that take arguments when they are followed by brackets, e.g. f vs f(x)
+var withArgumentsOpTypes = map[*operationType]*operationType{
+	callFunctionOpType:  callFunctionWithArgsOpType,
+	explodeStringOpType: explodeOpType,
+	pathsOpType:         pathsWithFilterOpType,
+	firstOpType:         firstWithArgsOpType,
+	lastOpType:          lastWithArgsOpType,
+	recurseOpType:       recurseWithArgsOpType,
+}
+
 func handleToken(tokens []*token, index int, postProcessedTokens []*token) (tokensAccum []*token, skipNextToken bool) {
 	skipNextToken = false
 	currentToken := tokens[index]
@@ -134,6 +455,16 @@ func handleToken(tokens []*token, index int, postProcessedTokens []*token) (toke
 		}
 	}
 
+	if index != len(tokens)-1 && currentToken.TokenType == operationToken &&
+		tokens[index+1].TokenType == openBracket {
+		if withArgsOpType, hasArgsVariant := withArgumentsOpTypes[currentToken.Operation.OperationType]; hasArgsVariant {
+			log.Debug("  its a %v call with arguments", withArgsOpType.Type)
+			op := *currentToken.Operation
+			op.OperationType = withArgsOpType
+			currentToken.Operation = &op
+		}
+	}
+
 	if index != len(tokens)-1 && currentToken.AssignOperation != nil &&
 		tokenIsOpType(tokens[index+1], assignOpType) {
 		log.Debug("  its an update assign")
@@ -1,6 +1,8 @@
 package yqlib
 
 import (
+	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -29,6 +31,18 @@ var participleYqRules = []*participleYqRule{
 	{"AsignAsVariable", `as`, opTokenWithPrefs(assignVariableOpType, nil, assignVarPreferences{}), 0},
 	{"AsignRefVariable", `ref`, opTokenWithPrefs(assignVariableOpType, nil, assignVarPreferences{IsReference: true}), 0},
 
+	{"DefineFunction", `def\s+[a-zA-Z_][a-zA-Z_0-9]*(\s*\([^\)]*\))?\s*:`, defineFunctionOpToken(), 0},
+
+	{"If", `if`, literalToken(openIf, false), 0},
+	{"Then", `then`, literalToken(thenIf, false), 0},
+	{"Elif", `elif`, literalToken(elifIf, false), 0},
+	{"Else", `else`, literalToken(elseIf, false), 0},
+	{"End", `end`, literalToken(closeIf, true), 0},
+
+	{"Try", `try`, opToken(tryOpType), 0},
+	{"Catch", `catch`, opToken(catchOpType), 0},
+	{"Optional", `\?`, literalOpToken(optionalOpType, true), 0},
+
 	{"CreateMap", `:\s*`, opToken(createMapOpType), 0},
 	simpleOp("length", lengthOpType),
 	simpleOp("line", lineOpType),
@@ -36,8 +50,23 @@ var participleYqRules = []*participleYqRule{
 	simpleOp("eval", evalOpType),
 
 	{"MapValues", `map_?values`, opToken(mapValuesOpType), 0},
+	{"Walk", `walk`, opToken(walkOpType), 0},
+	{"Limit", `limit`, opToken(limitOpType), 0},
+	{"First", `first`, opTokenWithPrefs(firstOpType, nil, arrayElementPreferences{}), 0},
+	{"Last", `last`, opTokenWithPrefs(lastOpType, nil, arrayElementPreferences{Last: true}), 0},
+	{"Nth", `nth`, opToken(nthOpType), 0},
+	{"Range", `range`, opToken(rangeOpType), 0},
+	{"Until", `until`, opToken(untilOpType), 0},
+	{"Repeat", `repeat`, opToken(repeatOpType), 0},
+	{"Recurse", `recurse`, opTokenWithPrefs(recurseOpType, nil, recursiveDescentPreferences{
+		RecurseArray:        true,
+		TraversePreferences: traversePreferences{DontFollowAlias: true},
+	}), 0},
 	simpleOp("map", mapOpType),
 	simpleOp("pick", pickOpType),
+	{"Omit", `omit`, opToken(omitOpType), 0},
+	{"Filter", `filter`, opToken(filterOpType), 0},
+	{"MapKeys", `map_?keys`, opToken(mapKeysOpType), 0},
 
 	{"FlattenWithDepth", `flatten\([0-9]+\)`, flattenWithDepth(), 0},
 	{"Flatten", `flatten`, opTokenWithPrefs(flattenOpType, nil, flattenPreferences{depth: -1}), 0},
@@ -50,6 +79,10 @@ var participleYqRules = []*participleYqRule{
 	simpleOp("sortKeys", sortKeysOpType),
 	simpleOp("sort_?keys", sortKeysOpType),
 
+	{"IndexBy", `index_?by`, opToken(indexByOpType), 0},
+	{"ToArrayBy", `to_?array_?by`, opToken(toArrayByOpType), 0},
+	{"Transpose", `transpose`, opToken(transposeOpType), 0},
+	{"Pivot", `pivot`, opToken(pivotOpType), 0},
 	{"ArrayToMap", "array_?to_?map", expressionOpToken(`(.[] | select(. != null) ) as $i ireduce({}; .[$i | key] = $i)`), 0},
 
 	{"YamlEncodeWithIndent", `to_?yaml\([0-9]+\)`, encodeParseIndent(YamlOutputFormat), 0},
@@ -78,6 +111,28 @@ var participleYqRules = []*participleYqRule{
 	{"Base64d", `@base64d`, decodeOp(Base64InputFormat), 0},
 	{"Base64", `@base64`, encodeWithIndent(Base64OutputFormat, 0), 0},
 
+	{"Base32d", `@base32d`, decodeOp(Base32InputFormat), 0},
+	{"Base32", `@base32`, encodeWithIndent(Base32OutputFormat, 0), 0},
+
+	{"Hexd", `@hexd`, decodeOp(HexInputFormat), 0},
+	{"Hex", `@hex`, encodeWithIndent(HexOutputFormat, 0), 0},
+
+	{"ShEncode", `@sh`, encodeWithIndent(ShOutputFormat, 0), 0},
+	{"URIEncode", `@uri`, encodeWithIndent(URIOutputFormat, 0), 0},
+	{"HTMLEncode", `@html`, encodeWithIndent(HTMLOutputFormat, 0), 0},
+	{"TextEncode", `@text`, encodeWithIndent(TextOutputFormat, 0), 0},
+
+	{"Gunzip", `@gunzip`, decodeOp(GzipInputFormat), 0},
+	{"Gzip", `@gzip`, encodeWithIndent(GzipOutputFormat, 0), 0},
+
+	{"Zlibd", `@zlibd`, decodeOp(ZlibInputFormat), 0},
+	{"Zlib", `@zlib`, encodeWithIndent(ZlibOutputFormat, 0), 0},
+
+	{"MD5", `@md5`, encodeWithIndent(MD5OutputFormat, 0), 0},
+	{"SHA1", `@sha1`, encodeWithIndent(SHA1OutputFormat, 0), 0},
+	{"SHA256", `@sha256`, encodeWithIndent(SHA256OutputFormat, 0), 0},
+	{"SHA512", `@sha512`, encodeWithIndent(SHA512OutputFormat, 0), 0},
+
 	{"LoadXML", `load_?xml|xml_?load`, loadOp(NewXMLDecoder(ConfiguredXMLPreferences), false), 0},
 
 	{"LoadBase64", `load_?base64`, loadOp(NewBase64Decoder(), false), 0},
@@ -91,16 +146,35 @@ var participleYqRules = []*participleYqRule{
 	{"SplitDocument", `splitDoc|split_?doc`, opToken(splitDocumentOpType), 0},
 
 	simpleOp("select", selectOpType),
+	simpleOp("empty", emptyOpType),
 	simpleOp("has", hasOpType),
 	simpleOp("unique_?by", uniqueByOpType),
 	simpleOp("unique", uniqueOpType),
+	{"IntersectionBy", `intersection_?by`, opToken(intersectionByOpType), 0},
+	{"Intersection", `intersection`, opToken(intersectionOpType), 0},
+	{"DifferenceBy", `difference_?by`, opToken(differenceByOpType), 0},
+	{"UnionBy", `union_?by`, opToken(unionByOpType), 0},
+	{"HashJoin", `hash_?join|lookup`, opToken(hashJoinOpType), 0},
 
 	simpleOp("group_?by", groupByOpType),
-	simpleOp("explode", explodeOpType),
+
+	{"Sum", `add|sum`, opToken(sumOpType), 0},
+	{"Avg", `avg`, opToken(avgOpType), 0},
+	{"MinBy", `min_?by`, opTokenWithPrefs(minMaxByOpType, nil, minMaxPreferences{Max: false}), 0},
+	{"MaxBy", `max_?by`, opTokenWithPrefs(minMaxByOpType, nil, minMaxPreferences{Max: true}), 0},
+	{"Min", `min`, opTokenWithPrefs(minMaxOpType, nil, minMaxPreferences{Max: false}), 0},
+	{"Max", `max`, opTokenWithPrefs(minMaxOpType, nil, minMaxPreferences{Max: true}), 0},
+	{"CountBy", `count_?by`, opToken(countByOpType), 0},
+	{"Frequencies", `frequencies`, opToken(frequenciesOpType), 0},
+	// explode(.) explodes anchors, without arguments it explodes a string into codepoints
+	{"Explode", `explode`, opToken(explodeStringOpType), 0},
+	simpleOp("implode", implodeOpType),
 	simpleOp("or", orOpType),
 	simpleOp("and", andOpType),
 	simpleOp("not", notOpType),
 	simpleOp("ireduce", reduceOpType),
+	{"Reduce", `reduce`, literalToken(openReduce, false), 0},
+	{"Foreach", `foreach`, literalToken(openForeach, false), 0},
 
 	simpleOp("join", joinStringOpType),
 	simpleOp("sub", subStringOpType),
@@ -121,6 +195,17 @@ var participleYqRules = []*participleYqRule{
 
 	simpleOp("contains", containsOpType),
 	simpleOp("split", splitStringOpType),
+	{"StartsWith", `startswith`, opToken(startsWithOpType), 0},
+	{"EndsWith", `endswith`, opToken(endsWithOpType), 0},
+	{"Ltrimstr", `ltrimstr`, opToken(ltrimstrOpType), 0},
+	{"Rtrimstr", `rtrimstr`, opToken(rtrimstrOpType), 0},
+	{"Index", `index`, opTokenWithPrefs(indexOpType, nil, indexPreferences{Last: false}), 0},
+	{"Rindex", `rindex`, opTokenWithPrefs(indexOpType, nil, indexPreferences{Last: true}), 0},
+	{"Indices", `indices`, opToken(indicesOpType), 0},
+	{"RepeatString", `repeat_string`, opToken(repeatStringOpType), 0},
+	{"Ljust", `ljust`, opTokenWithPrefs(padStringOpType, nil, padPreferences{PadStart: false}), 0},
+	{"Rjust", `rjust`, opTokenWithPrefs(padStringOpType, nil, padPreferences{PadStart: true}), 0},
+	{"Substr", `substr`, opToken(substrOpType), 0},
 	simpleOp("parent", getParentOpType),
 
 	simpleOp("keys", keysOpType),
@@ -130,6 +215,16 @@ var participleYqRules = []*participleYqRule{
 	simpleOp("file_?name|fileName", getFilenameOpType),
 	simpleOp("file_?index|fileIndex|fi", getFileIndexOpType),
 	simpleOp("path", getPathOpType),
+	{"GetPathValue", `get_?path`, opToken(getPathValueOpType), 0},
+	{"Paths", `paths`, opToken(pathsOpType), 0},
+	{"ToJSONPointer", `to_?json_?pointer`, opToken(toJSONPointerOpType), 0},
+	{"FromJSONPointer", `from_?json_?pointer`, opToken(fromJSONPointerOpType), 0},
+	{"ToDottedPath", `to_?dotted_?path`, opToken(toDottedPathOpType), 0},
+	{"FromDottedPath", `from_?dotted_?path`, opToken(fromDottedPathOpType), 0},
+	{"ToStream", `to_?stream`, opToken(toStreamOpType), 0},
+	{"FromStream", `from_?stream`, opToken(fromStreamOpType), 0},
+	{"TruncateStream", `truncate_?stream`, opToken(truncateStreamOpType), 0},
+	{"LeafPaths", `leaf_?paths`, opTokenWithPrefs(pathsOpType, nil, pathsPreferences{LeavesOnly: true}), 0},
 	simpleOp("set_?path", setPathOpType),
 	simpleOp("del_?paths", delPathsOpType),
 
@@ -144,6 +239,11 @@ var participleYqRules = []*participleYqRule{
 
 	assignableOp("style", getStyleOpType, assignStyleOpType),
 	assignableOp("tag|type", getTagOpType, assignTagOpType),
+	{"ToNumber", `to_?number`, opToken(toNumberOpType), 0},
+	{"ToString", `to_?string`, opToken(toStringOpType), 0},
+	{"ToBool", `to_?bool(ean)?`, opToken(toBoolOpType), 0},
+	{"GetKind", `kind`, opToken(getKindOpType), 0},
+
 	assignableOp("anchor", getAnchorOpType, assignAnchorOpType),
 	assignableOp("alias", getAliasOpType, assignAliasOpType),
 
@@ -158,6 +258,20 @@ var participleYqRules = []*participleYqRule{
 	{"Uppercase", `upcase|ascii_?upcase`, opTokenWithPrefs(changeCaseOpType, nil, changeCasePrefs{ToUpperCase: true}), 0},
 	{"Downcase", `downcase|ascii_?downcase`, opTokenWithPrefs(changeCaseOpType, nil, changeCasePrefs{ToUpperCase: false}), 0},
 	simpleOp("trim", trimOpType),
+	{"ToCamel", `to_?camel`, opTokenWithPrefs(changeStringCaseOpType, nil, stringCasePreferences{Case: "camel"}), 0},
+	{"ToPascal", `to_?pascal`, opTokenWithPrefs(changeStringCaseOpType, nil, stringCasePreferences{Case: "pascal"}), 0},
+	{"ToSnake", `to_?snake`, opTokenWithPrefs(changeStringCaseOpType, nil, stringCasePreferences{Case: "snake"}), 0},
+	{"ToKebab", `to_?kebab`, opTokenWithPrefs(changeStringCaseOpType, nil, stringCasePreferences{Case: "kebab"}), 0},
+	{"ToTitle", `to_?title`, opTokenWithPrefs(changeStringCaseOpType, nil, stringCasePreferences{Case: "title"}), 0},
+	{"RenameKeys", `rename_?keys`, opToken(renameKeysOpType), 0},
+
+	{"Floor", `floor`, opToken(floorOpType), 0},
+	{"Ceil", `ceil`, opToken(ceilOpType), 0},
+	{"Round", `round`, opToken(roundOpType), 0},
+	{"Abs", `abs`, opToken(absOpType), 0},
+	{"Sqrt", `sqrt`, opToken(sqrtOpType), 0},
+	{"Log", `log`, opToken(logOpType), 0},
+	{"Pow", `pow`, opToken(powOpType), 0},
 
 	{"HexValue", `0[xX][0-9A-Fa-f]+`, hexValue(), 0},
 	{"FloatValueScientific", `-?[1-9](\.\d+)?[Ee][-+]?\d+`, floatValue(), 0},
@@ -170,10 +284,12 @@ var participleYqRules = []*participleYqRule{
 
 	{"NullValue", `[Nn][Uu][Ll][Ll]|~`, nullValue(), 0},
 
-	{"QuotedStringValue", `"([^"\\]*(\\.[^"\\]*)*)"`, stringValue(), 0},
+	// strings may contain interpolated expressions, e.g. "\(.a + "b")", which can themselves contain strings
+	{"QuotedStringValue", `"([^"\\]|\\\((?:[^"()]|"(?:[^"\\]|\\.)*"|\((?:[^"()]|"(?:[^"\\]|\\.)*")*\))*\)|\\.)*"`, stringValue(), 0},
 
 	{"StrEnvOp", `strenv\([^\)]+\)`, envOp(true), 0},
 	{"EnvOp", `env\([^\)]+\)`, envOp(false), 0},
+	{"GetEnv", `getenv`, opToken(getenvOpType), 0},
 
 	{"EnvSubstWithOptions", `envsubst\((ne|nu|ff| |,)+\)`, envSubstWithOptions(), 0},
 	simpleOp("envsubst", envsubstOpType),
@@ -207,6 +323,15 @@ var participleYqRules = []*participleYqRule{
 
 	{"SubtractAssign", `\-=`, opToken(subtractAssignOpType), 0},
 	{"Subtract", `\-`, opToken(subtractOpType), 0},
+
+	{"DivideAssign", `\/=`, opToken(divideAssignOpType), 0},
+	{"Divide", `\/`, opToken(divideOpType), 0},
+
+	{"ModuloAssign", `%=`, opToken(moduloAssignOpType), 0},
+	{"Modulo", `%`, opToken(moduloOpType), 0},
+
+	// must be last, so that operators with the same name take precedence
+	{"CallFunction", `[a-zA-Z_][a-zA-Z_0-9]*`, callFunctionOpToken(), 0},
 }
 
 type yqAction func(lexer.Token) (*token, error)
@@ -235,7 +360,7 @@ func newParticipleLexer() expressionTokeniser {
 	for i, yqRule := range participleYqRules {
 		simpleRules[i] = lexer.SimpleRule{Name: yqRule.Name, Pattern: yqRule.Pattern}
 	}
-	lexerDefinition := lexer.MustSimple(simpleRules)
+	lexerDefinition := lexer.MustSimple(simpleRules, lexer.MatchLongest())
 	symbols := lexerDefinition.Symbols()
 
 	for _, yqRule := range participleYqRules {
@@ -355,8 +480,16 @@ func stringValue() yqAction {
 		log.Debug("rawTokenvalue: %v", rawToken.Value)
 		value := unwrap(rawToken.Value)
 		log.Debug("unwrapped: %v", value)
-		value = strings.ReplaceAll(value, "\\\"", "\"")
-		value = strings.ReplaceAll(value, "\\n", "\n")
+		if strings.Contains(value, "\\(") {
+			// "\(.a) and \(.b)"
+			operation, err := createStringInterpolationOperation(value)
+			if err != nil {
+				return nil, err
+			} else if operation != nil {
+				return &token{TokenType: operationToken, Operation: operation}, nil
+			}
+		}
+		value = unescapeString(value)
 		log.Debug("replaced: %v", value)
 		return &token{TokenType: operationToken, Operation: createValueOperation(value, value)}, nil
 	}
@@ -444,6 +577,37 @@ func getVariableOpToken() yqAction {
 	}
 }
 
+var functionDefinitionParser = regexp.MustCompile(`^def\s+([a-zA-Z_][a-zA-Z_0-9]*)\s*(?:\(([^\)]*)\))?\s*:$`)
+var functionParamParser = regexp.MustCompile(`^\$?[a-zA-Z_][a-zA-Z_0-9]*$`)
+
+func defineFunctionOpToken() yqAction {
+	return func(rawToken lexer.Token) (*token, error) {
+		value := rawToken.Value
+		matches := functionDefinitionParser.FindStringSubmatch(value)
+		if matches == nil {
+			return nil, fmt.Errorf("bad function definition '%v'", value)
+		}
+		prefs := defineFunctionPreferences{Name: matches[1], Params: []string{}}
+		if strings.TrimSpace(matches[2]) != "" {
+			for _, param := range strings.Split(matches[2], ";") {
+				param = strings.TrimSpace(param)
+				if !functionParamParser.MatchString(param) {
+					return nil, fmt.Errorf("bad parameter '%v' in function definition '%v'", param, value)
+				}
+				prefs.Params = append(prefs.Params, param)
+			}
+		}
+		op := &Operation{OperationType: defineFunctionOpType, Value: defineFunctionOpType.Type, StringValue: prefs.Name, Preferences: prefs}
+		return &token{TokenType: operationToken, Operation: op}, nil
+	}
+}
+
+func callFunctionOpToken() yqAction {
+	return func(rawToken lexer.Token) (*token, error) {
+		return callFunctionToken(rawToken.Value), nil
+	}
+}
+
 func hexValue() yqAction {
 	return func(rawToken lexer.Token) (*token, error) {
 		var originalString = rawToken.Value
@@ -510,6 +674,13 @@ func loadOp(decoder Decoder, loadAsString bool) yqAction {
 	return opTokenWithPrefs(loadOpType, nil, prefs)
 }
 
+func literalOpToken(op *operationType, checkForPost bool) yqAction {
+	return func(rawToken lexer.Token) (*token, error) {
+		op := &Operation{OperationType: op, Value: op.Type, StringValue: rawToken.Value}
+		return &token{TokenType: operationToken, Operation: op, CheckForPostTraverse: checkForPost}, nil
+	}
+}
+
 func opToken(op *operationType) yqAction {
 	return opTokenWithPrefs(op, nil, nil)
 }
@@ -541,6 +712,18 @@ func (p *participleLexer) Tokenise(expression string) ([]*token, error) {
 		if e != nil {
 			return nil, e
 		} else if rawToken.Type == lexer.EOF {
+			tokens, e = expandConditionals(tokens)
+			if e != nil {
+				return nil, e
+			}
+			tokens, e = expandReduce(tokens)
+			if e != nil {
+				return nil, e
+			}
+			tokens, e = expandFunctionDefinitions(tokens)
+			if e != nil {
+				return nil, e
+			}
 			return postProcessTokens(tokens), nil
 		}
 
@@ -604,6 +604,22 @@ var participleLexerScenarios = []participleLexerScenario{
 			},
 		},
 	},
+	{
+		expression: "@sh",
+		tokens: []*token{
+			{
+				TokenType: operationToken,
+				Operation: &Operation{
+					OperationType: encodeOpType,
+					Value:         "ENCODE",
+					StringValue:   "@sh",
+					Preferences: encoderPreferences{
+						format: ShOutputFormat,
+					},
+				},
+			},
+		},
+	},
 	{
 		expression: "@yamld",
 		tokens: []*token{
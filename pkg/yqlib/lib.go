@@ -40,8 +40,16 @@ type operationType struct {
 var orOpType = &operationType{Type: "OR", NumArgs: 2, Precedence: 20, Handler: orOperator}
 var andOpType = &operationType{Type: "AND", NumArgs: 2, Precedence: 20, Handler: andOperator}
 var reduceOpType = &operationType{Type: "REDUCE", NumArgs: 2, Precedence: 35, Handler: reduceOperator}
+var reduceEachOpType = &operationType{Type: "REDUCE_EACH", NumArgs: 2, Precedence: 35, Handler: reduceEachOperator}
+var foreachOpType = &operationType{Type: "FOREACH", NumArgs: 2, Precedence: 35, Handler: foreachOperator}
 
-var blockOpType = &operationType{Type: "BLOCK", Precedence: 10, NumArgs: 2, Handler: emptyOperator}
+var blockOpType = &operationType{Type: "BLOCK", Precedence: 10, NumArgs: 2, Handler: blockOperator}
+
+var ifOpType = &operationType{Type: "IF", NumArgs: 2, Precedence: 10, Handler: ifOperator}
+
+var defineFunctionOpType = &operationType{Type: "DEFINE_FUNCTION", NumArgs: 1, Precedence: 50, Handler: emptyOperator}
+var callFunctionOpType = &operationType{Type: "CALL_FUNCTION", NumArgs: 0, Precedence: 50, Handler: callFunctionOperator}
+var callFunctionWithArgsOpType = &operationType{Type: "CALL_FUNCTION", NumArgs: 1, Precedence: 50, Handler: callFunctionOperator}
 
 var unionOpType = &operationType{Type: "UNION", NumArgs: 2, Precedence: 10, Handler: unionOperator}
 
@@ -50,6 +58,8 @@ var pipeOpType = &operationType{Type: "PIPE", NumArgs: 2, Precedence: 30, Handle
 var assignOpType = &operationType{Type: "ASSIGN", NumArgs: 2, Precedence: 40, Handler: assignUpdateOperator}
 var addAssignOpType = &operationType{Type: "ADD_ASSIGN", NumArgs: 2, Precedence: 40, Handler: addAssignOperator}
 var subtractAssignOpType = &operationType{Type: "SUBTRACT_ASSIGN", NumArgs: 2, Precedence: 40, Handler: subtractAssignOperator}
+var divideAssignOpType = &operationType{Type: "DIVIDE_ASSIGN", NumArgs: 2, Precedence: 40, Handler: divideAssignOperator}
+var moduloAssignOpType = &operationType{Type: "MODULO_ASSIGN", NumArgs: 2, Precedence: 40, Handler: moduloAssignOperator}
 
 var assignAttributesOpType = &operationType{Type: "ASSIGN_ATTRIBUTES", NumArgs: 2, Precedence: 40, Handler: assignAttributesOperator}
 var assignStyleOpType = &operationType{Type: "ASSIGN_STYLE", NumArgs: 2, Precedence: 40, Handler: assignStyleOperator}
@@ -59,11 +69,17 @@ var assignCommentOpType = &operationType{Type: "ASSIGN_COMMENT", NumArgs: 2, Pre
 var assignAnchorOpType = &operationType{Type: "ASSIGN_ANCHOR", NumArgs: 2, Precedence: 40, Handler: assignAnchorOperator}
 var assignAliasOpType = &operationType{Type: "ASSIGN_ALIAS", NumArgs: 2, Precedence: 40, Handler: assignAliasOperator}
 
+var catchOpType = &operationType{Type: "CATCH", NumArgs: 2, Precedence: 43, Handler: catchOperator}
+var optionalOpType = &operationType{Type: "OPTIONAL", NumArgs: 1, Precedence: 44, Handler: tryOperator}
+var tryOpType = &operationType{Type: "TRY", NumArgs: 1, Precedence: 45, Handler: tryOperator}
+
 var multiplyOpType = &operationType{Type: "MULTIPLY", NumArgs: 2, Precedence: 42, Handler: multiplyOperator}
 var multiplyAssignOpType = &operationType{Type: "MULTIPLY_ASSIGN", NumArgs: 2, Precedence: 42, Handler: multiplyAssignOperator}
 
 var addOpType = &operationType{Type: "ADD", NumArgs: 2, Precedence: 42, Handler: addOperator}
 var subtractOpType = &operationType{Type: "SUBTRACT", NumArgs: 2, Precedence: 42, Handler: subtractOperator}
+var divideOpType = &operationType{Type: "DIVIDE", NumArgs: 2, Precedence: 42, Handler: divideOperator}
+var moduloOpType = &operationType{Type: "MODULO", NumArgs: 2, Precedence: 42, Handler: moduloOperator}
 var alternativeOpType = &operationType{Type: "ALTERNATIVE", NumArgs: 2, Precedence: 42, Handler: alternativeOperator}
 
 var equalsOpType = &operationType{Type: "EQUALS", NumArgs: 2, Precedence: 40, Handler: equalsOperator}
@@ -77,6 +93,14 @@ var createMapOpType = &operationType{Type: "CREATE_MAP", NumArgs: 2, Precedence:
 var shortPipeOpType = &operationType{Type: "SHORT_PIPE", NumArgs: 2, Precedence: 45, Handler: pipeOperator}
 
 var lengthOpType = &operationType{Type: "LENGTH", NumArgs: 0, Precedence: 50, Handler: lengthOperator}
+
+var floorOpType = &operationType{Type: "FLOOR", NumArgs: 0, Precedence: 50, Handler: floorOperator}
+var ceilOpType = &operationType{Type: "CEIL", NumArgs: 0, Precedence: 50, Handler: ceilOperator}
+var roundOpType = &operationType{Type: "ROUND", NumArgs: 0, Precedence: 50, Handler: roundOperator}
+var absOpType = &operationType{Type: "ABS", NumArgs: 0, Precedence: 50, Handler: absOperator}
+var sqrtOpType = &operationType{Type: "SQRT", NumArgs: 0, Precedence: 50, Handler: sqrtOperator}
+var logOpType = &operationType{Type: "LOG", NumArgs: 0, Precedence: 50, Handler: logOperator}
+var powOpType = &operationType{Type: "POW", NumArgs: 1, Precedence: 50, Handler: powOperator}
 var lineOpType = &operationType{Type: "LINE", NumArgs: 0, Precedence: 50, Handler: lineOperator}
 var columnOpType = &operationType{Type: "LINE", NumArgs: 0, Precedence: 50, Handler: columnOperator}
 
@@ -86,6 +110,9 @@ var collectOpType = &operationType{Type: "COLLECT", NumArgs: 1, Precedence: 50,
 var mapOpType = &operationType{Type: "MAP", NumArgs: 1, Precedence: 50, Handler: mapOperator}
 var errorOpType = &operationType{Type: "ERROR", NumArgs: 1, Precedence: 50, Handler: errorOperator}
 var pickOpType = &operationType{Type: "PICK", NumArgs: 1, Precedence: 50, Handler: pickOperator}
+var omitOpType = &operationType{Type: "OMIT", NumArgs: 1, Precedence: 50, Handler: omitOperator}
+var filterOpType = &operationType{Type: "FILTER", NumArgs: 1, Precedence: 50, Handler: filterOperator}
+var mapKeysOpType = &operationType{Type: "MAP_KEYS", NumArgs: 1, Precedence: 50, Handler: mapKeysOperator}
 var evalOpType = &operationType{Type: "EVAL", NumArgs: 1, Precedence: 50, Handler: evalOperator}
 var mapValuesOpType = &operationType{Type: "MAP_VALUES", NumArgs: 1, Precedence: 50, Handler: mapValuesOperator}
 
@@ -105,6 +132,16 @@ var allConditionOpType = &operationType{Type: "ALL_CONDITION", NumArgs: 1, Prece
 
 var toEntriesOpType = &operationType{Type: "TO_ENTRIES", NumArgs: 0, Precedence: 50, Handler: toEntriesOperator}
 var fromEntriesOpType = &operationType{Type: "FROM_ENTRIES", NumArgs: 0, Precedence: 50, Handler: fromEntriesOperator}
+var limitOpType = &operationType{Type: "LIMIT", NumArgs: 1, Precedence: 50, Handler: limitOperator}
+var firstOpType = &operationType{Type: "FIRST", NumArgs: 0, Precedence: 50, Handler: arrayElementOperator}
+var firstWithArgsOpType = &operationType{Type: "FIRST_WITH_ARGS", NumArgs: 1, Precedence: 50, Handler: firstWithArgsOperator}
+var lastOpType = &operationType{Type: "LAST", NumArgs: 0, Precedence: 50, Handler: arrayElementOperator}
+var lastWithArgsOpType = &operationType{Type: "LAST_WITH_ARGS", NumArgs: 1, Precedence: 50, Handler: lastWithArgsOperator}
+var nthOpType = &operationType{Type: "NTH", NumArgs: 1, Precedence: 50, Handler: nthOperator}
+var rangeOpType = &operationType{Type: "RANGE", NumArgs: 1, Precedence: 50, Handler: rangeOperator}
+var untilOpType = &operationType{Type: "UNTIL", NumArgs: 1, Precedence: 50, Handler: untilOperator}
+var repeatOpType = &operationType{Type: "REPEAT", NumArgs: 1, Precedence: 50, Handler: repeatOperator}
+var walkOpType = &operationType{Type: "WALK", NumArgs: 1, Precedence: 50, Handler: walkOperator}
 var withEntriesOpType = &operationType{Type: "WITH_ENTRIES", NumArgs: 1, Precedence: 50, Handler: withEntriesOperator}
 
 var withOpType = &operationType{Type: "WITH", NumArgs: 1, Precedence: 50, Handler: withOperator}
@@ -112,6 +149,13 @@ var withOpType = &operationType{Type: "WITH", NumArgs: 1, Precedence: 50, Handle
 var splitDocumentOpType = &operationType{Type: "SPLIT_DOC", NumArgs: 0, Precedence: 50, Handler: splitDocumentOperator}
 var getVariableOpType = &operationType{Type: "GET_VARIABLE", NumArgs: 0, Precedence: 55, Handler: getVariableOperator}
 var getStyleOpType = &operationType{Type: "GET_STYLE", NumArgs: 0, Precedence: 50, Handler: getStyleOperator}
+var stringInterpolationOpType = &operationType{Type: "STRING_INTERPOLATION", NumArgs: 0, Precedence: 50, Handler: stringInterpolationOperator}
+
+var toNumberOpType = &operationType{Type: "TO_NUMBER", NumArgs: 0, Precedence: 50, Handler: toNumberOperator}
+var toStringOpType = &operationType{Type: "TO_STRING", NumArgs: 0, Precedence: 50, Handler: toStringOperator}
+var toBoolOpType = &operationType{Type: "TO_BOOL", NumArgs: 0, Precedence: 50, Handler: toBoolOperator}
+var getKindOpType = &operationType{Type: "GET_KIND", NumArgs: 0, Precedence: 50, Handler: getKindOperator}
+
 var getTagOpType = &operationType{Type: "GET_TAG", NumArgs: 0, Precedence: 50, Handler: getTagOperator}
 
 var getKeyOpType = &operationType{Type: "GET_KEY", NumArgs: 0, Precedence: 50, Handler: getKeyOperator}
@@ -126,6 +170,16 @@ var getFilenameOpType = &operationType{Type: "GET_FILENAME", NumArgs: 0, Precede
 var getFileIndexOpType = &operationType{Type: "GET_FILE_INDEX", NumArgs: 0, Precedence: 50, Handler: getFileIndexOperator}
 
 var getPathOpType = &operationType{Type: "GET_PATH", NumArgs: 0, Precedence: 50, Handler: getPathOperator}
+var getPathValueOpType = &operationType{Type: "GET_PATH_VALUE", NumArgs: 1, Precedence: 50, Handler: getPathValueOperator}
+var pathsOpType = &operationType{Type: "PATHS", NumArgs: 0, Precedence: 50, Handler: pathsOperator}
+var pathsWithFilterOpType = &operationType{Type: "PATHS_WITH_FILTER", NumArgs: 1, Precedence: 50, Handler: pathsWithFilterOperator}
+var toJSONPointerOpType = &operationType{Type: "TO_JSON_POINTER", NumArgs: 0, Precedence: 50, Handler: toJSONPointerOperator}
+var fromJSONPointerOpType = &operationType{Type: "FROM_JSON_POINTER", NumArgs: 0, Precedence: 50, Handler: fromJSONPointerOperator}
+var toDottedPathOpType = &operationType{Type: "TO_DOTTED_PATH", NumArgs: 0, Precedence: 50, Handler: toDottedPathOperator}
+var fromDottedPathOpType = &operationType{Type: "FROM_DOTTED_PATH", NumArgs: 0, Precedence: 50, Handler: fromDottedPathOperator}
+var toStreamOpType = &operationType{Type: "TO_STREAM", NumArgs: 0, Precedence: 50, Handler: toStreamOperator}
+var fromStreamOpType = &operationType{Type: "FROM_STREAM", NumArgs: 1, Precedence: 50, Handler: fromStreamOperator}
+var truncateStreamOpType = &operationType{Type: "TRUNCATE_STREAM", NumArgs: 1, Precedence: 50, Handler: truncateStreamOperator}
 var setPathOpType = &operationType{Type: "SET_PATH", NumArgs: 1, Precedence: 50, Handler: setPathOperator}
 var delPathsOpType = &operationType{Type: "DEL_PATHS", NumArgs: 1, Precedence: 50, Handler: delPathsOperator}
 
@@ -144,6 +198,19 @@ var testOpType = &operationType{Type: "TEST", NumArgs: 1, Precedence: 50, Handle
 var splitStringOpType = &operationType{Type: "SPLIT", NumArgs: 1, Precedence: 50, Handler: splitStringOperator}
 var changeCaseOpType = &operationType{Type: "CHANGE_CASE", NumArgs: 0, Precedence: 50, Handler: changeCaseOperator}
 var trimOpType = &operationType{Type: "TRIM", NumArgs: 0, Precedence: 50, Handler: trimSpaceOperator}
+var changeStringCaseOpType = &operationType{Type: "CHANGE_STRING_CASE", NumArgs: 0, Precedence: 50, Handler: changeStringCaseOperator}
+var renameKeysOpType = &operationType{Type: "RENAME_KEYS", NumArgs: 1, Precedence: 50, Handler: renameKeysOperator}
+var startsWithOpType = &operationType{Type: "STARTS_WITH", NumArgs: 1, Precedence: 50, Handler: startsWithOperator}
+var endsWithOpType = &operationType{Type: "ENDS_WITH", NumArgs: 1, Precedence: 50, Handler: endsWithOperator}
+var ltrimstrOpType = &operationType{Type: "LTRIMSTR", NumArgs: 1, Precedence: 50, Handler: ltrimstrOperator}
+var rtrimstrOpType = &operationType{Type: "RTRIMSTR", NumArgs: 1, Precedence: 50, Handler: rtrimstrOperator}
+var indexOpType = &operationType{Type: "INDEX", NumArgs: 1, Precedence: 50, Handler: indexOperator}
+var indicesOpType = &operationType{Type: "INDICES", NumArgs: 1, Precedence: 50, Handler: indicesOperator}
+var repeatStringOpType = &operationType{Type: "REPEAT_STRING", NumArgs: 1, Precedence: 50, Handler: repeatStringOperator}
+var padStringOpType = &operationType{Type: "PAD_STRING", NumArgs: 1, Precedence: 50, Handler: padStringOperator}
+var substrOpType = &operationType{Type: "SUBSTR", NumArgs: 1, Precedence: 50, Handler: substrOperator}
+var explodeStringOpType = &operationType{Type: "EXPLODE_STRING", NumArgs: 0, Precedence: 50, Handler: explodeStringOperator}
+var implodeOpType = &operationType{Type: "IMPLODE", NumArgs: 0, Precedence: 50, Handler: implodeOperator}
 
 var loadOpType = &operationType{Type: "LOAD", NumArgs: 1, Precedence: 50, Handler: loadYamlOperator}
 
@@ -156,17 +223,36 @@ var traverseArrayOpType = &operationType{Type: "TRAVERSE_ARRAY", NumArgs: 2, Pre
 var selfReferenceOpType = &operationType{Type: "SELF", NumArgs: 0, Precedence: 55, Handler: selfOperator}
 var valueOpType = &operationType{Type: "VALUE", NumArgs: 0, Precedence: 50, Handler: valueOperator}
 var envOpType = &operationType{Type: "ENV", NumArgs: 0, Precedence: 50, Handler: envOperator}
+var getenvOpType = &operationType{Type: "GETENV", NumArgs: 1, Precedence: 50, Handler: getenvOperator}
 var notOpType = &operationType{Type: "NOT", NumArgs: 0, Precedence: 50, Handler: notOperator}
 var emptyOpType = &operationType{Type: "EMPTY", Precedence: 50, Handler: emptyOperator}
 
 var envsubstOpType = &operationType{Type: "ENVSUBST", NumArgs: 0, Precedence: 50, Handler: envsubstOperator}
 
 var recursiveDescentOpType = &operationType{Type: "RECURSIVE_DESCENT", NumArgs: 0, Precedence: 50, Handler: recursiveDescentOperator}
+var recurseOpType = &operationType{Type: "RECURSE", NumArgs: 0, Precedence: 50, Handler: recursiveDescentOperator}
+var recurseWithArgsOpType = &operationType{Type: "RECURSE_WITH_ARGS", NumArgs: 1, Precedence: 50, Handler: recurseOperator}
 
 var selectOpType = &operationType{Type: "SELECT", NumArgs: 1, Precedence: 50, Handler: selectOperator}
 var hasOpType = &operationType{Type: "HAS", NumArgs: 1, Precedence: 50, Handler: hasOperator}
 var uniqueOpType = &operationType{Type: "UNIQUE", NumArgs: 0, Precedence: 50, Handler: unique}
 var uniqueByOpType = &operationType{Type: "UNIQUE_BY", NumArgs: 1, Precedence: 50, Handler: uniqueBy}
+var intersectionOpType = &operationType{Type: "INTERSECTION", NumArgs: 0, Precedence: 50, Handler: intersectionOperator}
+var intersectionByOpType = &operationType{Type: "INTERSECTION_BY", NumArgs: 1, Precedence: 50, Handler: intersectionByOperator}
+var differenceByOpType = &operationType{Type: "DIFFERENCE_BY", NumArgs: 1, Precedence: 50, Handler: differenceByOperator}
+var unionByOpType = &operationType{Type: "UNION_BY", NumArgs: 1, Precedence: 50, Handler: unionByOperator}
+var transposeOpType = &operationType{Type: "TRANSPOSE", NumArgs: 0, Precedence: 50, Handler: transposeOperator}
+var pivotOpType = &operationType{Type: "PIVOT", NumArgs: 1, Precedence: 50, Handler: pivotOperator}
+var indexByOpType = &operationType{Type: "INDEX_BY", NumArgs: 1, Precedence: 50, Handler: indexByOperator}
+var toArrayByOpType = &operationType{Type: "TO_ARRAY_BY", NumArgs: 1, Precedence: 50, Handler: toArrayByOperator}
+var hashJoinOpType = &operationType{Type: "HASH_JOIN", NumArgs: 1, Precedence: 50, Handler: hashJoinOperator}
+var sumOpType = &operationType{Type: "SUM", NumArgs: 0, Precedence: 50, Handler: sumOperator}
+var avgOpType = &operationType{Type: "AVG", NumArgs: 0, Precedence: 50, Handler: avgOperator}
+var minMaxOpType = &operationType{Type: "MIN_MAX", NumArgs: 0, Precedence: 50, Handler: minMaxOperator}
+var minMaxByOpType = &operationType{Type: "MIN_MAX_BY", NumArgs: 1, Precedence: 50, Handler: minMaxByOperator}
+var countByOpType = &operationType{Type: "COUNT_BY", NumArgs: 1, Precedence: 50, Handler: countByOperator}
+var frequenciesOpType = &operationType{Type: "FREQUENCIES", NumArgs: 0, Precedence: 50, Handler: frequenciesOperator}
+
 var groupByOpType = &operationType{Type: "GROUP_BY", NumArgs: 1, Precedence: 50, Handler: groupBy}
 var flattenOpType = &operationType{Type: "FLATTEN_BY", NumArgs: 0, Precedence: 50, Handler: flattenOp}
 var deleteChildOpType = &operationType{Type: "DELETE", NumArgs: 1, Precedence: 40, Handler: deleteChildOperator}
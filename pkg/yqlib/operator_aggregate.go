@@ -0,0 +1,211 @@
+package yqlib
+
+import (
+	"container/list"
+	"fmt"
+
+	"github.com/elliotchance/orderedmap"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func sumOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- sumOperator")
+	results := list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		candidateNode := unwrapDoc(candidate.Node)
+		if candidateNode.Kind != yaml.SequenceNode {
+			return Context{}, fmt.Errorf("add only works with arrays, got %v (%v)", candidate.GetNiceTag(), candidate.GetNicePath())
+		}
+		sum, err := sumArray(d, context.ReadOnlyClone(), candidate, candidateNode)
+		if err != nil {
+			return Context{}, err
+		}
+		results.PushBack(sum)
+	}
+
+	return context.ChildContext(results), nil
+}
+
+// sumArray adds all the elements of the array together, the same way `+` does,
+// so numbers are summed, strings and arrays concatenated and maps merged.
+func sumArray(d *dataTreeNavigator, context Context, candidate *CandidateNode, candidateNode *yaml.Node) (*CandidateNode, error) {
+	sum := candidate.CreateReplacement(createScalarNode(nil, "null"))
+	for i, child := range candidateNode.Content {
+		var err error
+		sum, err = add(d, context, sum, candidate.CreateChildInArray(i, child))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sum, nil
+}
+
+func avgOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- avgOperator")
+	results := list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		candidateNode := unwrapDoc(candidate.Node)
+		if candidateNode.Kind != yaml.SequenceNode {
+			return Context{}, fmt.Errorf("avg only works with arrays, got %v (%v)", candidate.GetNiceTag(), candidate.GetNicePath())
+		}
+		if len(candidateNode.Content) == 0 {
+			results.PushBack(candidate.CreateReplacement(createScalarNode(nil, "null")))
+			continue
+		}
+
+		for i, child := range candidateNode.Content {
+			if _, err := parseMathNumber("avg", candidate.CreateChildInArray(i, child)); err != nil {
+				return Context{}, err
+			}
+		}
+
+		sum, err := sumArray(d, context.ReadOnlyClone(), candidate, candidateNode)
+		if err != nil {
+			return Context{}, err
+		}
+		count := len(candidateNode.Content)
+		average, err := divide(d, context, sum, candidate.CreateReplacement(createScalarNode(count, fmt.Sprintf("%v", count))))
+		if err != nil {
+			return Context{}, err
+		}
+		results.PushBack(average)
+	}
+
+	return context.ChildContext(results), nil
+}
+
+type minMaxPreferences struct {
+	Max bool
+}
+
+func minMaxOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	selfExpression := &ExpressionNode{Operation: &Operation{OperationType: selfReferenceOpType}}
+	expressionNode.RHS = selfExpression
+	return minMaxByOperator(d, context, expressionNode)
+}
+
+// min_by and max_by use the same ordering as sort_by, if several elements
+// are equally small (or large) then, like jq, the first minimum and the last
+// maximum are returned.
+func minMaxByOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	prefs := expressionNode.Operation.Preferences.(minMaxPreferences)
+	operation := "min"
+	if prefs.Max {
+		operation = "max"
+	}
+	log.Debugf("-- minMaxByOperator %v", operation)
+
+	results := list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		candidateNode := unwrapDoc(candidate.Node)
+		if candidateNode.Kind != yaml.SequenceNode {
+			return Context{}, fmt.Errorf("%v only works with arrays, got %v (%v)", operation, candidate.GetNiceTag(), candidate.GetNicePath())
+		}
+		if len(candidateNode.Content) == 0 {
+			results.PushBack(candidate.CreateReplacement(createScalarNode(nil, "null")))
+			continue
+		}
+
+		sortableArray, err := createSortableNodeArray(d, context, candidate, candidateNode, expressionNode.RHS, operation)
+		if err != nil {
+			return Context{}, err
+		}
+
+		chosen := 0
+		for i := 1; i < len(sortableArray); i++ {
+			if prefs.Max && !sortableArray.Less(i, chosen) {
+				chosen = i
+			} else if !prefs.Max && sortableArray.Less(i, chosen) {
+				chosen = i
+			}
+		}
+		results.PushBack(candidate.CreateChildInArray(chosen, candidateNode.Content[chosen]))
+	}
+
+	return context.ChildContext(results), nil
+}
+
+func frequenciesOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	selfExpression := &ExpressionNode{Operation: &Operation{OperationType: selfReferenceOpType}}
+	expressionNode.RHS = selfExpression
+	return countByOperator(d, context, expressionNode)
+}
+
+type countByGroup struct {
+	key   *yaml.Node
+	count int
+}
+
+func countByOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- countByOperator")
+	results := list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		candidateNode := unwrapDoc(candidate.Node)
+		if candidateNode.Kind != yaml.SequenceNode {
+			return Context{}, fmt.Errorf("only arrays are supported for count_by, got %v (%v)", candidate.GetNiceTag(), candidate.GetNicePath())
+		}
+
+		groups, err := countIntoGroups(d, context, expressionNode.RHS, candidateNode)
+		if err != nil {
+			return Context{}, err
+		}
+
+		resultNode := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for groupEl := groups.Front(); groupEl != nil; groupEl = groupEl.Next() {
+			group := groupEl.Value.(*countByGroup)
+			resultNode.Content = append(resultNode.Content,
+				group.key,
+				createScalarNode(group.count, fmt.Sprintf("%v", group.count)))
+		}
+		results.PushBack(candidate.CreateReplacement(resultNode))
+	}
+
+	return context.ChildContext(results), nil
+}
+
+// countIntoGroups counts the elements by the canonical encoding of their key, so that
+// keys of different types (e.g. 1 and "1") are not counted together. As they would be
+// duplicate keys in the result, that is an error.
+func countIntoGroups(d *dataTreeNavigator, context Context, keyExpression *ExpressionNode, node *yaml.Node) (*orderedmap.OrderedMap, error) {
+	groups := orderedmap.NewOrderedMap()
+	encodedKeysByValue := map[string]string{}
+	for _, child := range node.Content {
+		rhs, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(&CandidateNode{Node: child}), keyExpression)
+		if err != nil {
+			return nil, err
+		}
+
+		keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+		if rhs.MatchingNodes.Len() > 0 {
+			keyNode = resolveAlias(unwrapDoc(rhs.MatchingNodes.Front().Value.(*CandidateNode).Node))
+		}
+		encodedKey, err := canonicalEncoding(keyNode)
+		if err != nil {
+			return nil, err
+		}
+
+		group, exists := groups.Get(encodedKey)
+		if !exists {
+			key := createStringScalarNode(encodedKey)
+			if keyNode.Kind == yaml.ScalarNode {
+				key = &yaml.Node{Kind: yaml.ScalarNode, Tag: keyNode.Tag, Value: keyNode.Value}
+			}
+			if otherKey, exists := encodedKeysByValue[key.Value]; exists {
+				return nil, fmt.Errorf("cannot count by both %v and %v, they would be duplicate keys", otherKey, encodedKey)
+			}
+			encodedKeysByValue[key.Value] = encodedKey
+			group = &countByGroup{key: key}
+			groups.Set(encodedKey, group)
+		}
+		group.(*countByGroup).count++
+	}
+	return groups, nil
+}
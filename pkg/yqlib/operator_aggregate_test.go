@@ -0,0 +1,193 @@
+package yqlib
+
+import (
+	"testing"
+)
+
+var aggregateOperatorScenarios = []expressionScenario{
+	{
+		description: "Sum numbers",
+		document:    `[1, 2, 3.5]`,
+		expression:  `add`,
+		expected: []string{
+			"D0, P[], (!!float)::6.5\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   `[1, 2, 3]`,
+		expression: `sum`,
+		expected: []string{
+			"D0, P[], (!!int)::6\n",
+		},
+	},
+	{
+		description:    "Add arrays together",
+		subdescription: "Works with anything that can be added with `+`, e.g. strings, arrays and maps",
+		document:       `[[cat], [dog, frog]]`,
+		expression:     `add`,
+		expected: []string{
+			"D0, P[], (!!seq)::[cat, dog, frog]\n",
+		},
+	},
+	{
+		description: "Merge maps",
+		document:    `[{a: 1, b: 2}, {b: 3}]`,
+		expression:  `add`,
+		expected: []string{
+			"D0, P[], (!!map)::{a: 1, b: 3}\n",
+		},
+	},
+	{
+		description: "Adding an empty array gives null",
+		document:    `[]`,
+		expression:  `add`,
+		expected: []string{
+			"D0, P[], (!!null)::null\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		document:      `[1, [2]]`,
+		expression:    `add`,
+		expectedError: "!!seq (1) cannot be added to a !!int ()",
+	},
+	{
+		skipDoc:       true,
+		document:      `a: 1`,
+		expression:    `.a | add`,
+		expectedError: "add only works with arrays, got !!int (a)",
+	},
+	{
+		description: "Average",
+		document:    `[1, 2, 3, 4]`,
+		expression:  `avg`,
+		expected: []string{
+			"D0, P[], (!!float)::2.5\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   `[1, 2, 3]`,
+		expression: `avg`,
+		expected: []string{
+			"D0, P[], (!!int)::2\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   `[]`,
+		expression: `avg`,
+		expected: []string{
+			"D0, P[], (!!null)::null\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		document:      `[1, cat]`,
+		expression:    `avg`,
+		expectedError: "avg only works with numbers, got !!str (1)",
+	},
+	{
+		description: "Minimum and maximum",
+		document:    `[3, 1.5, 10]`,
+		expression:  `min, max`,
+		expected: []string{
+			"D0, P[1], (!!float)::1.5\n",
+			"D0, P[2], (!!int)::10\n",
+		},
+	},
+	{
+		description:    "Minimum and maximum of strings",
+		subdescription: "Anything that can be sorted can be compared",
+		document:       `[banana, apple, cherry]`,
+		expression:     `min, max`,
+		expected: []string{
+			"D0, P[1], (!!str)::apple\n",
+			"D0, P[2], (!!str)::cherry\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   `[]`,
+		expression: `min`,
+		expected: []string{
+			"D0, P[], (!!null)::null\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		document:      `a: 1`,
+		expression:    `.a | max`,
+		expectedError: "max only works with arrays, got !!int (a)",
+	},
+	{
+		skipDoc:       true,
+		document:      `[[1], [2]]`,
+		expression:    `max`,
+		expectedError: "max only works for scalars, got !!seq",
+	},
+	{
+		description:    "Minimum and maximum by an expression",
+		subdescription: "If there are several minimums the first is returned, if there are several maximums the last is returned",
+		document:       `[{name: a, size: 3}, {name: b, size: 1}, {name: c, size: 1}, {name: d, size: 3}]`,
+		expression:     `min_by(.size), max_by(.size)`,
+		expected: []string{
+			"D0, P[1], (!!map)::{name: b, size: 1}\n",
+			"D0, P[3], (!!map)::{name: d, size: 3}\n",
+		},
+	},
+	{
+		description: "Find the newest image tag",
+		document:    `[{tag: v1, created: 2021-01-05T03:10:00Z}, {tag: v2, created: 2022-01-05T03:10:00Z}, {tag: v0, created: 2020-01-05T03:10:00Z}]`,
+		expression:  `max_by(.created) | .tag`,
+		expected: []string{
+			"D0, P[1 tag], (!!str)::v2\n",
+		},
+	},
+	{
+		description: "Count by an expression",
+		document:    `[{name: a, team: red}, {name: b, team: blue}, {name: c, team: red}]`,
+		expression:  `count_by(.team)`,
+		expected: []string{
+			"D0, P[], (!!map)::red: 2\nblue: 1\n",
+		},
+	},
+	{
+		description: "Frequencies",
+		document:    `[cat, dog, cat, cat, frog]`,
+		expression:  `frequencies`,
+		expected: []string{
+			"D0, P[], (!!map)::cat: 3\ndog: 1\nfrog: 1\n",
+		},
+	},
+	{
+		description: "Frequencies of maps",
+		skipDoc:     true,
+		document:    `[1, 1, null, {a: 1}, {a: 1}]`,
+		expression:  `frequencies`,
+		expected: []string{
+			"D0, P[], (!!map)::1: 2\nnull: 1\n'{\"a\":1}': 2\n",
+		},
+	},
+	{
+		description:   "Frequencies of keys with different types",
+		skipDoc:       true,
+		document:      `[1, "1", 1]`,
+		expression:    `frequencies`,
+		expectedError: `cannot count by both 1 and "1", they would be duplicate keys`,
+	},
+	{
+		skipDoc:       true,
+		document:      `a: cat`,
+		expression:    `.a | frequencies`,
+		expectedError: "only arrays are supported for count_by, got !!str (a)",
+	},
+}
+
+func TestAggregateOperatorScenarios(t *testing.T) {
+	for _, tt := range aggregateOperatorScenarios {
+		testScenario(t, &tt)
+	}
+	documentOperatorScenarios(t, "aggregation", aggregateOperatorScenarios)
+}
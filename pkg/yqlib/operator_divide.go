@@ -0,0 +1,123 @@
+package yqlib
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+func createDivideOp(lhs *ExpressionNode, rhs *ExpressionNode) *ExpressionNode {
+	return &ExpressionNode{Operation: &Operation{OperationType: divideOpType},
+		LHS: lhs,
+		RHS: rhs}
+}
+
+func divideAssignOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	return compoundAssignFunction(d, context, expressionNode, createDivideOp)
+}
+
+func divideOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("Divide operator")
+
+	return crossFunction(d, context.ReadOnlyClone(), expressionNode, divide, false)
+}
+
+func divide(d *dataTreeNavigator, context Context, lhs *CandidateNode, rhs *CandidateNode) (*CandidateNode, error) {
+	lhs.Node = unwrapDoc(lhs.Node)
+	rhs.Node = unwrapDoc(rhs.Node)
+
+	lhsNode := lhs.Node
+
+	if lhsNode.Tag == "!!null" {
+		return nil, fmt.Errorf("%v cannot be divided by %v", describeOperand(lhsNode.Tag, lhs), describeOperand(rhs.Node.Tag, rhs))
+	}
+
+	target := lhs.CreateReplacement(&yaml.Node{})
+
+	if lhsNode.Kind == yaml.ScalarNode && rhs.Node.Kind == yaml.ScalarNode {
+		target.Node.Kind = yaml.ScalarNode
+		target.Node.Style = lhsNode.Style
+		if err := divideScalars(target, lhs, rhs); err != nil {
+			return nil, err
+		}
+		return target, nil
+	}
+	return nil, fmt.Errorf("%v cannot be divided by %v", describeOperand(lhsNode.Tag, lhs), describeOperand(rhs.Node.Tag, rhs))
+}
+
+func divideScalars(target *CandidateNode, lhsC *CandidateNode, rhsC *CandidateNode) error {
+	lhs := lhsC.Node
+	rhs := rhsC.Node
+	lhsTag := lhs.Tag
+	rhsTag := guessTagFromCustomType(rhs)
+	lhsIsCustom := false
+	if !strings.HasPrefix(lhsTag, "!!") {
+		// custom tag - we have to have a guess
+		lhsTag = guessTagFromCustomType(lhs)
+		lhsIsCustom = true
+	}
+
+	if lhsTag == "!!str" && rhsTag == "!!str" {
+		// like jq, dividing a string by a string splits it
+		splitNode := split(lhs.Value, rhs.Value)
+		target.Node.Kind = splitNode.Kind
+		target.Node.Tag = splitNode.Tag
+		target.Node.Style = 0
+		target.Node.Content = splitNode.Content
+	} else if lhsTag == "!!int" && rhsTag == "!!int" {
+		format, lhsNum, err := parseInt64(lhs.Value)
+		if err != nil {
+			return err
+		}
+		_, rhsNum, err := parseInt64(rhs.Value)
+		if err != nil {
+			return err
+		}
+		if rhsNum == 0 {
+			return fmt.Errorf("%v cannot be divided by %v", describeOperand(lhs.Value, lhsC), describeOperand("zero", rhsC))
+		}
+		if lhsNum%rhsNum == 0 {
+			target.Node.Tag = lhs.Tag
+			target.Node.Value = fmt.Sprintf(format, lhsNum/rhsNum)
+		} else {
+			if lhsIsCustom {
+				target.Node.Tag = lhs.Tag
+			} else {
+				target.Node.Tag = "!!float"
+			}
+			target.Node.Value = fmt.Sprintf("%v", float64(lhsNum)/float64(rhsNum))
+		}
+	} else if (lhsTag == "!!int" || lhsTag == "!!float") && (rhsTag == "!!int" || rhsTag == "!!float") {
+		lhsNum, err := strconv.ParseFloat(lhs.Value, 64)
+		if err != nil {
+			return err
+		}
+		rhsNum, err := strconv.ParseFloat(rhs.Value, 64)
+		if err != nil {
+			return err
+		}
+		if rhsNum == 0 {
+			return fmt.Errorf("%v cannot be divided by %v", describeOperand(lhs.Value, lhsC), describeOperand("zero", rhsC))
+		}
+		if lhsIsCustom {
+			target.Node.Tag = lhs.Tag
+		} else {
+			target.Node.Tag = "!!float"
+		}
+		target.Node.Value = fmt.Sprintf("%v", lhsNum/rhsNum)
+	} else {
+		return fmt.Errorf("%v cannot be divided by %v", describeOperand(lhs.Tag, lhsC), describeOperand(rhs.Tag, rhsC))
+	}
+	return nil
+}
+
+// describeOperand describes an operand for an error message, with its path when it has one.
+func describeOperand(description string, candidate *CandidateNode) string {
+	path := candidate.GetNicePath()
+	if path == "" {
+		return description
+	}
+	return fmt.Sprintf("%v (%v)", description, path)
+}
@@ -0,0 +1,115 @@
+package yqlib
+
+import (
+	"testing"
+)
+
+var divideOperatorScenarios = []expressionScenario{
+	{
+		skipDoc:    true,
+		document:   `{}`,
+		expression: "(.a / .b) as $x",
+		expected: []string{
+			"D0, P[], (doc)::{}\n",
+		},
+	},
+	{
+		description: "String split",
+		document:    `{a: cat_meow, b: _}`,
+		expression:  `.c = .a / .b`,
+		expected: []string{
+			"D0, P[], (doc)::{a: cat_meow, b: _, c: [cat, meow]}\n",
+		},
+	},
+	{
+		description:    "Number division",
+		subdescription: "The result during division is calculated as a float",
+		document:       `{a: 12, b: 2.5}`,
+		expression:     `.a = .a / .b`,
+		expected: []string{
+			"D0, P[], (doc)::{a: 4.8, b: 2.5}\n",
+		},
+	},
+	{
+		description:    "Integer division",
+		subdescription: "If the integers divide evenly, the result is an integer. Otherwise it is a float.",
+		document:       `{a: 12, b: 5}`,
+		expression:     `.a /= 4 | .b /= 2`,
+		expected: []string{
+			"D0, P[], (doc)::{a: 3, b: 2.5}\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `12 / 4 | tag`,
+		expected: []string{
+			"D0, P[], (!!str)::!!int\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `12 / 5 | tag`,
+		expected: []string{
+			"D0, P[], (!!str)::!!float\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `0xC / 2`,
+		expected: []string{
+			"D0, P[], (!!int)::0x6\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `"" / ","`,
+		expected: []string{
+			"D0, P[], (!!seq)::[]\n",
+		},
+	},
+	{
+		description: "Custom types: that are really numbers",
+		document:    "a: !horse 12\nb: !goat 5",
+		expression:  `.a = .a / .b`,
+		expected: []string{
+			"D0, P[], (doc)::a: !horse 2.4\nb: !goat 5\n",
+		},
+	},
+	{
+		description:    "Division by zero",
+		subdescription: "Dividing by zero is an error",
+		document:       `{a: 1, b: 0}`,
+		expression:     `.a / .b`,
+		expectedError:  "1 (a) cannot be divided by zero (b)",
+	},
+	{
+		skipDoc:       true,
+		document:      `{a: 1.5, b: 0.0}`,
+		expression:    `.a / .b`,
+		expectedError: "1.5 (a) cannot be divided by zero (b)",
+	},
+	{
+		skipDoc:       true,
+		document:      `{a: cat, b: 2}`,
+		expression:    `.a / .b`,
+		expectedError: "!!str (a) cannot be divided by !!int (b)",
+	},
+	{
+		skipDoc:       true,
+		document:      `{a: [1], b: 2}`,
+		expression:    `.a / .b`,
+		expectedError: "!!seq (a) cannot be divided by !!int (b)",
+	},
+	{
+		skipDoc:       true,
+		expression:    `1 / 0`,
+		expectedError: "1 cannot be divided by zero",
+	},
+}
+
+func TestDivideOperatorScenarios(t *testing.T) {
+	for _, tt := range divideOperatorScenarios {
+		testScenario(t, &tt)
+	}
+	documentOperatorScenarios(t, "divide", divideOperatorScenarios)
+}
@@ -26,6 +26,30 @@ func configureEncoder(format PrinterOutputFormat, indent int) Encoder {
 		return NewXMLEncoder(indent, ConfiguredXMLPreferences)
 	case Base64OutputFormat:
 		return NewBase64Encoder()
+	case Base32OutputFormat:
+		return NewBase32Encoder()
+	case HexOutputFormat:
+		return NewHexEncoder()
+	case ShOutputFormat:
+		return NewShEncoder()
+	case URIOutputFormat:
+		return NewURIEncoder()
+	case HTMLOutputFormat:
+		return NewHTMLEncoder()
+	case TextOutputFormat:
+		return NewTextEncoder()
+	case MD5OutputFormat:
+		return NewMD5Encoder()
+	case SHA1OutputFormat:
+		return NewSHA1Encoder()
+	case SHA256OutputFormat:
+		return NewSHA256Encoder()
+	case SHA512OutputFormat:
+		return NewSHA512Encoder()
+	case GzipOutputFormat:
+		return NewGzipEncoder()
+	case ZlibOutputFormat:
+		return NewZlibEncoder()
 	}
 	panic("invalid encoder")
 }
@@ -46,6 +70,10 @@ type encoderPreferences struct {
 	indent int
 }
 
+func isCompressedFormat(format PrinterOutputFormat) bool {
+	return format == GzipOutputFormat || format == ZlibOutputFormat
+}
+
 /* encodes object as yaml string */
 
 func encodeOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
@@ -66,8 +94,9 @@ func encodeOperator(d *dataTreeNavigator, context Context, expressionNode *Expre
 
 		// remove trailing newlines if needed.
 		// check if we originally decoded this path, and the original thing had a single line.
+		// compressed output is binary, so any trailing new line is part of the data.
 		originalList := context.GetVariable("decoded: " + candidate.GetKey())
-		if originalList != nil && originalList.Len() > 0 && hasOnlyOneNewLine.MatchString(stringValue) {
+		if !isCompressedFormat(preferences.format) && originalList != nil && originalList.Len() > 0 && hasOnlyOneNewLine.MatchString(stringValue) {
 
 			original := originalList.Front().Value.(*CandidateNode)
 			originalNode := unwrapDoc(original.Node)
@@ -107,6 +136,14 @@ func decodeOperator(d *dataTreeNavigator, context Context, expressionNode *Expre
 		decoder = NewXMLDecoder(ConfiguredXMLPreferences)
 	case Base64InputFormat:
 		decoder = NewBase64Decoder()
+	case Base32InputFormat:
+		decoder = NewBase32Decoder()
+	case HexInputFormat:
+		decoder = NewHexDecoder()
+	case GzipInputFormat:
+		decoder = NewGzipDecoder()
+	case ZlibInputFormat:
+		decoder = NewZlibDecoder()
 	case PropertiesInputFormat:
 		decoder = NewPropertiesDecoder()
 	case CSVObjectInputFormat:
@@ -259,6 +259,188 @@ var encoderDecoderOperatorScenarios = []expressionScenario{
 			"D0, P[], (doc)::coolData:\n    a: apple\n",
 		},
 	},
+	{
+		description: "Encode a string to base32",
+		document:    "coolData: a special string",
+		expression:  ".coolData | @base32",
+		expected: []string{
+			"D0, P[coolData], (!!str)::MEQHG4DFMNUWC3BAON2HE2LOM4======\n",
+		},
+	},
+	{
+		description: "Encode a top level string to base32",
+		skipDoc:     true,
+		document:    "hello",
+		expression:  "@base32",
+		expected: []string{
+			"D0, P[], (!!str)::NBSWY3DP\n",
+		},
+	},
+	{
+		description: "Decode a base32 encoded string",
+		document:    "coolData: MEQHG4DFMNUWC3BAON2HE2LOM4======",
+		expression:  ".coolData | @base32d",
+		expected: []string{
+			"D0, P[coolData], (!!str)::a special string\n",
+		},
+	},
+	{
+		description: "Encode a string to hex",
+		document:    "coolData: yq rocks",
+		expression:  ".coolData | @hex",
+		expected: []string{
+			"D0, P[coolData], (!!str)::797120726f636b73\n",
+		},
+	},
+	{
+		description: "Decode a hex encoded string",
+		document:    "coolData: 797120726f636b73",
+		expression:  ".coolData | @hexd",
+		expected: []string{
+			"D0, P[coolData], (!!str)::yq rocks\n",
+		},
+	},
+	{
+		description:    "Encode strings for use in a shell command",
+		subdescription: "Strings are wrapped in single quotes (with any single quotes escaped), arrays are joined with spaces.",
+		document:       "files: [a.txt, \"it's here.txt\", 3]",
+		expression:     `"rm " + (.files | @sh)`,
+		expected: []string{
+			"D0, P[], (!!str)::rm 'a.txt' 'it'\\''s here.txt' 3\n",
+		},
+	},
+	{
+		description:   "Encode a map for use in a shell command",
+		skipDoc:       true,
+		document:      "a: {b: c}",
+		expression:    `.a | @sh`,
+		expectedError: "cannot encode !!map as sh, can only operate on scalars and arrays of scalars",
+	},
+	{
+		description:    "Encode a string for use in a url",
+		subdescription: "Percent-encodes everything except unreserved characters.",
+		document:       "query: cats & dogs/ü",
+		expression:     `"https://example.com/search?q=" + (.query | @uri)`,
+		expected: []string{
+			"D0, P[], (!!str)::https://example.com/search?q=cats%20%26%20dogs%2F%C3%BC\n",
+		},
+	},
+	{
+		description: "Encode a string for html",
+		document:    `title: "<b>Tom & Jerry's</b>"`,
+		expression:  `.title | @html`,
+		expected: []string{
+			"D0, P[title], (!!str)::&lt;b&gt;Tom &amp; Jerry&#39;s&lt;/b&gt;\n",
+		},
+	},
+	{
+		description:    "Encode as text",
+		subdescription: "Like `to_string`, scalars are printed as is and maps and arrays are encoded as json.",
+		document:       "a: {b: [1, 2]}",
+		expression:     `.a | @text`,
+		expected: []string{
+			"D0, P[a], (!!str)::{\"b\":[1,2]}\n",
+		},
+	},
+	{
+		description: "Use with string interpolation",
+		document:    "name: it's me",
+		expression:  `"echo \(.name | @sh)"`,
+		expected: []string{
+			"D0, P[], (!!str)::echo 'it'\\''s me'\n",
+		},
+	},
+	{
+		description: "empty hex decode",
+		skipDoc:     true,
+		expression:  `"" | @hexd`,
+		expected: []string{
+			"D0, P[], (!!str)::\n",
+		},
+	},
+	{
+		description: "Hash a string with sha256",
+		document:    "coolData: a special string",
+		expression:  ".coolData | @sha256",
+		expected: []string{
+			"D0, P[coolData], (!!str)::995e1d575eef93a49b7c5a1dcdaee47a32563f2848a15afa3741bfa7b9fb65af\n",
+		},
+	},
+	{
+		description: "Hash a string with md5",
+		document:    "coolData: a special string",
+		expression:  ".coolData | @md5",
+		expected: []string{
+			"D0, P[coolData], (!!str)::8f0febbed08b42be60d7e7a9e7f2b134\n",
+		},
+	},
+	{
+		description: "Hash a string with sha1",
+		skipDoc:     true,
+		document:    "coolData: a special string",
+		expression:  ".coolData | @sha1",
+		expected: []string{
+			"D0, P[coolData], (!!str)::1883bec08e797f40ecb94c6a65fedc0f2e1b3c82\n",
+		},
+	},
+	{
+		description: "Hash a string with sha512",
+		skipDoc:     true,
+		document:    "coolData: a special string",
+		expression:  ".coolData | @sha512",
+		expected: []string{
+			"D0, P[coolData], (!!str)::d4bf11286230e190e2ba387788393d9313d34a01bd0f52c0300f257062de71f86d51c2351c47c80e2c4b03df4d9e6ae0002b39800af40f3919ebef73359e3f9b\n",
+		},
+	},
+	{
+		description:    "Hash a map",
+		subdescription: "Maps and arrays are hashed using a canonical encoding (compact json with sorted keys), so key order, comments and styles do not affect the result.",
+		document:       "a: {z: 1, y: [2, {q: x, p: \"y\"}]} # comment\nb:\n  y:\n    - 2\n    - p: y\n      q: x\n  z: 1\n",
+		expression:     `.a |= @md5 | .b |= @md5`,
+		expected: []string{
+			"D0, P[], (doc)::a: 1bac25b622438afed70ecf17c1bdbb29 # comment\nb: 1bac25b622438afed70ecf17c1bdbb29\n",
+		},
+	},
+	{
+		description:    "Gzip and base64 encode a string",
+		subdescription: "Compressed data is binary, so pipe it through `@base64` to get a printable string.",
+		document:       "coolData: hello",
+		expression:     ".coolData | @gzip | @base64",
+		expected: []string{
+			"D0, P[coolData], (!!str)::H4sIAAAAAAAA/wAFAPr/aGVsbG8DAIamEDYFAAAA\n",
+		},
+	},
+	{
+		description:    "Edit a gzipped, base64 encoded yaml document",
+		subdescription: "Decode the blob, update it and then encode it back again.",
+		document:       "coolData: H4sIAAAAAAAAA0u0UkjiSrZSMOQCAJzstX0KAAAA",
+		expression:     ".coolData |= (@base64d | @gunzip | from_yaml | .a = \"cool\" | @yaml | @gzip | @base64) | .coolData | @base64d | @gunzip",
+		expected: []string{
+			"D0, P[coolData], (!!str)::a: cool\nc: 1\n\n",
+		},
+	},
+	{
+		description: "Zlib compress and decompress a string",
+		document:    "coolData: hello",
+		expression:  ".coolData | @zlib | @base64 | @base64d | @zlibd",
+		expected: []string{
+			"D0, P[coolData], (!!str)::hello\n",
+		},
+	},
+	{
+		description:   "Decompress invalid gzip data",
+		skipDoc:       true,
+		expression:    `"cat" | @gunzip`,
+		expectedError: "could not decompress gzip data: unexpected EOF",
+	},
+	{
+		description: "empty gunzip",
+		skipDoc:     true,
+		expression:  `"" | @gunzip`,
+		expected: []string{
+			"D0, P[], (!!str)::\n",
+		},
+	},
 	{
 		description: "empty base64 decode",
 		skipDoc:     true,
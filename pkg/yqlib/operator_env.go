@@ -4,6 +4,7 @@ import (
 	"container/list"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	parse "github.com/a8m/envsubst/parse"
@@ -18,6 +19,9 @@ type envOpPreferences struct {
 }
 
 func envOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	if ConfiguredSecurityPreferences.DisableEnvOps {
+		return Context{}, errEnvOpsDisabled
+	}
 	envName := expressionNode.Operation.CandidateNode.Node.Value
 	log.Debug("EnvOperator, env name:", envName)
 
@@ -54,6 +58,9 @@ func envOperator(d *dataTreeNavigator, context Context, expressionNode *Expressi
 }
 
 func envsubstOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	if ConfiguredSecurityPreferences.DisableEnvOps {
+		return Context{}, errEnvOpsDisabled
+	}
 	var results = list.New()
 	preferences := envOpPreferences{}
 	if expressionNode.Operation.Preferences != nil {
@@ -88,3 +95,52 @@ func envsubstOperator(d *dataTreeNavigator, context Context, expressionNode *Exp
 
 	return context.ChildContext(results), nil
 }
+
+// getenvOperator reads the environment variable named by the expression, which
+// (unlike env) is evaluated at runtime. Unset variables are null.
+func getenvOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	if ConfiguredSecurityPreferences.DisableEnvOps {
+		return Context{}, errEnvOpsDisabled
+	}
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		names, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(candidate), expressionNode.RHS)
+		if err != nil {
+			return Context{}, err
+		}
+
+		for nameEl := names.MatchingNodes.Front(); nameEl != nil; nameEl = nameEl.Next() {
+			envName, err := getStringArgument("getenv", unwrapDoc(nameEl.Value.(*CandidateNode).Node))
+			if err != nil {
+				return Context{}, err
+			}
+			log.Debug("getenvOperator, env name:", envName)
+
+			node := createScalarNode(nil, "null")
+			if rawValue, exists := os.LookupEnv(envName); exists {
+				node = createStringScalarNode(rawValue)
+			}
+			results.PushBack(candidate.CreateReplacement(node))
+		}
+	}
+
+	return context.ChildContext(results), nil
+}
+
+// envVariable is the value of $ENV, a map of all the environment variables sorted by name.
+func envVariable() (*list.List, error) {
+	if ConfiguredSecurityPreferences.DisableEnvOps {
+		return nil, errEnvOpsDisabled
+	}
+	environment := os.Environ()
+	sort.Strings(environment)
+
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, entry := range environment {
+		name, value, _ := strings.Cut(entry, "=")
+		node.Content = append(node.Content, createStringScalarNode(name), createStringScalarNode(value))
+	}
+	return (&CandidateNode{Node: node}).AsList(), nil
+}
@@ -150,6 +150,47 @@ var envOperatorScenarios = []expressionScenario{
 		expression:    `"the ${notThere} ${alsoNotThere}" | envsubst(nu,ff)`,
 		expectedError: "variable ${notThere} not set",
 	},
+	{
+		description:          "Read all environment variables with $ENV",
+		subdescription:       "$ENV is a map of all the environment variables, sorted by name.",
+		environmentVariables: map[string]string{"APP_NAME": "web", "APP_PORT": "80"},
+		expression:           `.data = ($ENV | with_entries(select(.key | test("^APP_"))))`,
+		expected: []string{
+			"D0, P[], ()::data:\n    APP_NAME: web\n    APP_PORT: \"80\"\n",
+		},
+	},
+	{
+		description:          "Read an environment variable with a computed name",
+		subdescription:       "Unlike `env` and `strenv`, the name given to `getenv` is an expression. The value is always a string, or null if it is not set.",
+		environmentVariables: map[string]string{"DB_HOST": "localhost"},
+		document:             "service: db\n",
+		expression:           `.host = getenv((.service | upcase) + "_HOST")`,
+		expected: []string{
+			"D0, P[], (doc)::service: db\nhost: localhost\n",
+		},
+	},
+	{
+		description: "getenv of an unset variable",
+		skipDoc:     true,
+		expression:  `getenv("NOT_THERE_AT_ALL")`,
+		expected: []string{
+			"D0, P[], (!!null)::null\n",
+		},
+	},
+	{
+		description: "$ENV can be shadowed",
+		skipDoc:     true,
+		expression:  `"cat" as $ENV | $ENV`,
+		expected: []string{
+			"D0, P[], (!!str)::cat\n",
+		},
+	},
+	{
+		description:   "getenv needs a string",
+		skipDoc:       true,
+		expression:    `getenv(1)`,
+		expectedError: "getenv expects a string argument, got !!int",
+	},
 	{
 		description:          "with header/footer",
 		skipDoc:              true,
@@ -168,3 +209,31 @@ func TestEnvOperatorScenarios(t *testing.T) {
 	}
 	documentOperatorScenarios(t, "env-variable-operators", envOperatorScenarios)
 }
+
+var envOpsDisabledScenarios = []expressionScenario{
+	{
+		expression:    `env(HOME)`,
+		expectedError: "env operations have been disabled",
+	},
+	{
+		expression:    `"${HOME}" | envsubst`,
+		expectedError: "env operations have been disabled",
+	},
+	{
+		expression:    `getenv("HOME")`,
+		expectedError: "env operations have been disabled",
+	},
+	{
+		expression:    `$ENV`,
+		expectedError: "env operations have been disabled",
+	},
+}
+
+func TestEnvOperatorsDisabled(t *testing.T) {
+	ConfiguredSecurityPreferences.DisableEnvOps = true
+	defer func() { ConfiguredSecurityPreferences.DisableEnvOps = false }()
+
+	for _, tt := range envOpsDisabledScenarios {
+		testScenario(t, &tt)
+	}
+}
@@ -0,0 +1,91 @@
+package yqlib
+
+import (
+	"container/list"
+	"fmt"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// filterOperator keeps the array elements, or map entries, where the expression is true.
+// Unlike map(select(f)), maps stay as maps.
+func filterOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- filterOperator")
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		node := unwrapDoc(candidate.Node)
+
+		filteredContent := make([]*yaml.Node, 0)
+		switch node.Kind {
+		case yaml.SequenceNode:
+			for i, child := range node.Content {
+				matches, err := matchesFilter(d, context.SingleReadonlyChildContext(candidate.CreateChildInArray(i, child)), expressionNode.RHS)
+				if err != nil {
+					return Context{}, err
+				}
+				if matches {
+					filteredContent = append(filteredContent, deepClone(child))
+				}
+			}
+		case yaml.MappingNode:
+			for i := 0; i < len(node.Content); i = i + 2 {
+				key := node.Content[i]
+				value := node.Content[i+1]
+				matches, err := matchesFilter(d, context.SingleReadonlyChildContext(candidate.CreateChildInMap(key, value)), expressionNode.RHS)
+				if err != nil {
+					return Context{}, err
+				}
+				if matches {
+					filteredContent = append(filteredContent, deepClone(key), deepClone(value))
+				}
+			}
+		default:
+			return Context{}, fmt.Errorf("cannot filter type %v (%v), can only filter arrays and maps", node.Tag, candidate.GetNicePath())
+		}
+
+		newNode := deepCloneNoContent(node)
+		newNode.Content = filteredContent
+		results.PushBack(candidate.CreateReplacementWithDocWrappers(newNode))
+	}
+
+	return context.ChildContext(results), nil
+}
+
+// mapKeysOperator applies the expression to each key of a map. Unlike rename_keys,
+// it is not recursive - nested maps are left as they are.
+func mapKeysOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- mapKeysOperator")
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		node := unwrapDoc(candidate.Node)
+		if node.Kind != yaml.MappingNode {
+			return Context{}, fmt.Errorf("cannot map_keys of type %v (%v), can only map the keys of maps", node.Tag, candidate.GetNicePath())
+		}
+
+		newNode := deepCloneNoContent(node)
+		newNode.Content = make([]*yaml.Node, len(node.Content))
+		for i := 0; i < len(node.Content); i = i + 2 {
+			key := node.Content[i]
+			newKey := key
+			if key.Tag != "!!merge" {
+				var err error
+				newKey, err = renameKey(d, context, candidate, key, expressionNode.RHS)
+				if err != nil {
+					return Context{}, err
+				}
+			}
+			newNode.Content[i] = newKey
+			newNode.Content[i+1] = node.Content[i+1]
+		}
+		if err := checkRenamedKeysAreUnique(candidate, node, newNode); err != nil {
+			return Context{}, err
+		}
+		results.PushBack(candidate.CreateReplacementWithDocWrappers(newNode))
+	}
+
+	return context.ChildContext(results), nil
+}
@@ -0,0 +1,90 @@
+package yqlib
+
+import (
+	"testing"
+)
+
+var filterOperatorScenarios = []expressionScenario{
+	{
+		description: "Filter an array",
+		document:    "[1, 2, 3, 4]",
+		expression:  `filter(. > 2)`,
+		expected: []string{
+			"D0, P[], (!!seq)::[3, 4]\n",
+		},
+	},
+	{
+		description:    "Filter a map",
+		subdescription: "Keeps the entries where the expression is true for the value. Unlike `map(select(f))`, the result is still a map.",
+		document:       "a: 1 # one\nb: 2\nc: 3 # three\n",
+		expression:     `filter(. != 2)`,
+		expected: []string{
+			"D0, P[], (!!map)::a: 1 # one\nc: 3 # three\n",
+		},
+	},
+	{
+		description:    "Filter a map by key",
+		subdescription: "The expression can use `key` to get the key of each entry.",
+		document:       "app_name: web\napp_port: 80\ndebug: true\n",
+		expression:     `filter(key | test("^app_"))`,
+		expected: []string{
+			"D0, P[], (!!map)::app_name: web\napp_port: 80\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		document:      "cat",
+		expression:    `filter(true)`,
+		expectedError: "cannot filter type !!str (), can only filter arrays and maps",
+	},
+	{
+		description:    "Map keys",
+		subdescription: "Applies the expression to each key of the map, keeping comments on the values. Unlike `rename_keys`, nested maps are left as they are.",
+		document:       "a: 1 # one\nb:\n  # nested\n  c: 2\n",
+		expression:     `map_keys(upcase)`,
+		expected: []string{
+			"D0, P[], (!!map)::A: 1 # one\nB:\n    # nested\n    c: 2\n",
+		},
+	},
+	{
+		description: "Map keys of a nested map",
+		document:    "config: {my_name: web, my_port: 80}\n",
+		expression:  `.config |= map_keys(sub("^my_", ""))`,
+		expected: []string{
+			"D0, P[], (doc)::config: {name: web, port: 80}\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   "a: &a {b: 1}\nc:\n  !!merge <<: *a\n  d: 2\n",
+		expression: `.c | map_keys(upcase)`,
+		expected: []string{
+			"D0, P[c], (!!map)::!!merge <<: *a\nD: 2\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		document:      "a: 1",
+		expression:    `map_keys([.])`,
+		expectedError: "cannot rename key a to a !!seq, keys must be scalars",
+	},
+	{
+		skipDoc:       true,
+		document:      "[a]",
+		expression:    `map_keys(upcase)`,
+		expectedError: "cannot map_keys of type !!seq (), can only map the keys of maps",
+	},
+	{
+		skipDoc:       true,
+		document:      "{a: {b: 2, B: 3}}",
+		expression:    `.a | map_keys(downcase)`,
+		expectedError: "cannot rename keys a.b and a.B, both would be renamed to 'b'",
+	},
+}
+
+func TestFilterOperatorScenarios(t *testing.T) {
+	for _, tt := range filterOperatorScenarios {
+		testScenario(t, &tt)
+	}
+	documentOperatorScenarios(t, "filter", filterOperatorScenarios)
+}
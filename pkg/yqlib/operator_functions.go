@@ -0,0 +1,170 @@
+package yqlib
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+)
+
+type defineFunctionPreferences struct {
+	Name   string
+	Params []string
+}
+
+type functionDefinition struct {
+	params []string
+	body   *ExpressionNode
+	// functions are lexically scoped, the body is evaluated with the
+	// variables and functions that were visible where it was defined.
+	variables map[string]*list.List
+	functions map[string]*functionDefinition
+}
+
+func functionKey(name string, arity int) string {
+	return fmt.Sprintf("%v/%v", name, arity)
+}
+
+func copyVariables(variables map[string]*list.List) map[string]*list.List {
+	copied := make(map[string]*list.List, len(variables))
+	for name, value := range variables {
+		copied[name] = value
+	}
+	return copied
+}
+
+func blockOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	// blocks are generally just containers for arguments, e.g. with(path; update),
+	// unless they are scoping a function definition:
+	// def f: body; rest
+	if expressionNode.LHS == nil || expressionNode.LHS.Operation.OperationType != defineFunctionOpType {
+		return emptyOperator(d, context, expressionNode)
+	}
+	return defineFunction(d, context, expressionNode.LHS, expressionNode.RHS)
+}
+
+func defineFunction(d *dataTreeNavigator, context Context, definitionNode *ExpressionNode, scopeNode *ExpressionNode) (Context, error) {
+	prefs := definitionNode.Operation.Preferences.(defineFunctionPreferences)
+	log.Debugf("-- defineFunction %v/%v", prefs.Name, len(prefs.Params))
+
+	scope := context.ChildContext(context.MatchingNodes)
+
+	definition := &functionDefinition{
+		params:    prefs.Params,
+		body:      definitionNode.RHS,
+		variables: copyVariables(context.Variables),
+	}
+	scope.setFunction(prefs.Name, len(prefs.Params), definition)
+	// the function can see itself, so it can be recursive
+	definition.functions = scope.functions
+
+	result, err := d.GetMatchingNodes(scope, scopeNode)
+	if err != nil {
+		return Context{}, err
+	}
+	return context.ChildContext(result.MatchingNodes), nil
+}
+
+func getFunctionArguments(argsNode *ExpressionNode) []*ExpressionNode {
+	if argsNode == nil {
+		return []*ExpressionNode{}
+	}
+	// f(a; b; c) is parsed as a BLOCK(a, BLOCK(b, c))
+	// (a block with a function definition on the LHS is an argument in its own right)
+	if argsNode.Operation.OperationType == blockOpType &&
+		argsNode.LHS.Operation.OperationType != defineFunctionOpType {
+		return append([]*ExpressionNode{argsNode.LHS}, getFunctionArguments(argsNode.RHS)...)
+	}
+	return []*ExpressionNode{argsNode}
+}
+
+func hasValueParams(definition *functionDefinition) bool {
+	for _, param := range definition.params {
+		if strings.HasPrefix(param, "$") {
+			return true
+		}
+	}
+	return false
+}
+
+func callFunctionOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	name := expressionNode.Operation.StringValue
+	args := getFunctionArguments(expressionNode.RHS)
+	log.Debugf("-- callFunction %v/%v", name, len(args))
+
+	definition := context.getFunction(name, len(args))
+	if definition == nil {
+		return Context{}, fmt.Errorf("%v/%v is not defined", name, len(args))
+	}
+
+	if !hasValueParams(definition) {
+		return evaluateFunction(d, context, definition, args)
+	}
+
+	// $params are bound per matching node, like jq
+	var results = list.New()
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		result, err := evaluateFunction(d, context.SingleChildContext(candidate), definition, args)
+		if err != nil {
+			return Context{}, err
+		}
+		results.PushBackList(result.MatchingNodes)
+	}
+	return context.ChildContext(results), nil
+}
+
+func evaluateFunction(d *dataTreeNavigator, context Context, definition *functionDefinition, args []*ExpressionNode) (Context, error) {
+	functionContext := context.ChildContext(context.MatchingNodes)
+	functionContext.Variables = copyVariables(definition.variables)
+	functionContext.functions = definition.functions
+
+	for i, param := range definition.params {
+		// parameters are closures, evaluated in the scope of the caller
+		paramName := strings.TrimPrefix(param, "$")
+		closure := &functionDefinition{
+			body:      args[i],
+			variables: copyVariables(context.Variables),
+			functions: context.functions,
+		}
+		functionContext.setFunction(paramName, 0, closure)
+	}
+
+	results, err := bindValueParams(d, context, functionContext, definition, args, 0)
+	if err != nil {
+		return Context{}, err
+	}
+	return context.ChildContext(results), nil
+}
+
+// bindValueParams evaluates the body once for each combination of $param values, as
+// def f($a): body is the same as def f(a): a as $a | body
+func bindValueParams(d *dataTreeNavigator, context Context, functionContext Context, definition *functionDefinition, args []*ExpressionNode, paramIndex int) (*list.List, error) {
+	if paramIndex == len(definition.params) {
+		result, err := d.GetMatchingNodes(functionContext, definition.body)
+		if err != nil {
+			return nil, err
+		}
+		return result.MatchingNodes, nil
+	}
+
+	param := definition.params[paramIndex]
+	if !strings.HasPrefix(param, "$") {
+		return bindValueParams(d, context, functionContext, definition, args, paramIndex+1)
+	}
+
+	values, err := d.GetMatchingNodes(context.ReadOnlyClone(), args[paramIndex])
+	if err != nil {
+		return nil, err
+	}
+	results := list.New()
+	for el := values.DeepClone().MatchingNodes.Front(); el != nil; el = el.Next() {
+		boundContext := functionContext.ChildContext(functionContext.MatchingNodes)
+		boundContext.SetVariable(strings.TrimPrefix(param, "$"), el.Value.(*CandidateNode).AsList())
+		result, err := bindValueParams(d, context, boundContext, definition, args, paramIndex+1)
+		if err != nil {
+			return nil, err
+		}
+		results.PushBackList(result)
+	}
+	return results, nil
+}
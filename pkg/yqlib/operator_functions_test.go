@@ -0,0 +1,213 @@
+package yqlib
+
+import (
+	"testing"
+)
+
+var functionOperatorScenarios = []expressionScenario{
+	{
+		description: "Define a function",
+		document:    `{a: 2, b: 3}`,
+		expression:  `def double: . * 2; .a |= double | .b |= double`,
+		expected: []string{
+			"D0, P[], (doc)::{a: 4, b: 6}\n",
+		},
+	},
+	{
+		description:    "Function with filter parameters",
+		subdescription: "Filter parameters are evaluated with the function input each time they are used.",
+		document:       `[{name: cat, sound: meow}, {name: dog, sound: woof}]`,
+		expression:     `def says(f; g): f + " says " + g; .[] | says(.name; .sound)`,
+		expected: []string{
+			"D0, P[0 name], (!!str)::cat says meow\n",
+			"D0, P[1 name], (!!str)::dog says woof\n",
+		},
+	},
+	{
+		description:    "Function with value parameters",
+		subdescription: "Value parameters are evaluated against each matching node, and are available as variables.",
+		document:       `[1, 2, 3]`,
+		expression:     `def scale($factor): map(. * $factor); scale(10)`,
+		expected: []string{
+			"D0, P[], (!!seq)::[10, 20, 30]\n",
+		},
+	},
+	{
+		description:    "Value parameters with several values",
+		subdescription: "Like jq, the function is called once for each value (and each combination of values, when there are several value parameters).",
+		expression:     `def pair($a; $b): [$a, $b]; pair((1, 2); ("x", "y"))`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 1\n- x\n",
+			"D0, P[], (!!seq)::- 1\n- y\n",
+			"D0, P[], (!!seq)::- 2\n- x\n",
+			"D0, P[], (!!seq)::- 2\n- y\n",
+		},
+	},
+	{
+		description: "Value parameter with several values",
+		skipDoc:     true,
+		expression:  `def f($a): [$a]; f(1, 2)`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 1\n",
+			"D0, P[], (!!seq)::- 2\n",
+		},
+	},
+	{
+		description: "Value parameter with no values",
+		skipDoc:     true,
+		expression:  `def f($a): [$a]; [f(.[])]`,
+		expected: []string{
+			"D0, P[], (!!seq)::[]\n",
+		},
+	},
+	{
+		description:    "Recursive functions",
+		subdescription: "Functions can call themselves",
+		document:       `5`,
+		expression:     `def fact: if . <= 1 then 1 else . * (. - 1 | fact) end; fact`,
+		expected: []string{
+			"D0, P[], (!!int)::120\n",
+		},
+	},
+	{
+		description:    "Functions are lexically scoped",
+		subdescription: "Functions see the variables and functions that were defined before them",
+		document:       `{a: cat, b: dog}`,
+		expression:     `.a as $x | def get: $x; .b as $x | get`,
+		expected: []string{
+			"D0, P[a], (!!str)::cat\n",
+		},
+	},
+	{
+		description:    "Functions can be used to update",
+		subdescription: "As long as the function returns a path into the document",
+		document:       `{a: {b: cat}}`,
+		expression:     `def target: .a.b; target = "dog"`,
+		expected: []string{
+			"D0, P[], (doc)::{a: {b: dog}}\n",
+		},
+	},
+	{
+		description: "Nested functions",
+		skipDoc:     true,
+		document:    `{a: 1}`,
+		expression:  `def f: def g: 3; g * 2; .a + f`,
+		expected: []string{
+			"D0, P[a], (!!int)::7\n",
+		},
+	},
+	{
+		description:   "Functions are scoped to the enclosing expression",
+		skipDoc:       true,
+		document:      `{a: 1}`,
+		expression:    `(def f: 3; f) | f`,
+		expectedError: "f/0 is not defined",
+	},
+	{
+		description:   "Calling a function with the wrong number of arguments",
+		skipDoc:       true,
+		document:      `{a: 1}`,
+		expression:    `def f(x): x; f`,
+		expectedError: "f/0 is not defined",
+	},
+	{
+		description: "Functions with the same name but different arity",
+		skipDoc:     true,
+		expression:  `def f: 1; def f(x): x + 1; [f, f(5)]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 1\n- 6\n",
+		},
+	},
+	{
+		description: "Filter parameters are closures",
+		skipDoc:     true,
+		expression:  `def apply(f): [1, 2] | map(f); 10 as $x | apply(. + $x)`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 11\n- 12\n",
+		},
+	},
+	{
+		description: "Function definition in a reduce block",
+		skipDoc:     true,
+		document:    `[1, 2, 3]`,
+		expression:  `.[] as $x ireduce(0; def plus: . + $x; plus)`,
+		expected: []string{
+			"D0, P[], (!!int)::6\n",
+		},
+	},
+	{
+		description: "Function definition after a pipe",
+		skipDoc:     true,
+		document:    `[1, 2, 3]`,
+		expression:  `.[] | def inc: . + 1; inc`,
+		expected: []string{
+			"D0, P[0], (!!int)::2\n",
+			"D0, P[1], (!!int)::3\n",
+			"D0, P[2], (!!int)::4\n",
+		},
+	},
+	{
+		description:    "Functions take precedence over builtins",
+		subdescription: "A function with the same name and number of arguments as a builtin replaces it, until the end of the expression",
+		document:       `[1, 2, 3]`,
+		expression:     `def add: "mine"; add`,
+		expected: []string{
+			"D0, P[], (!!str)::mine\n",
+		},
+	},
+	{
+		description: "Function named after a builtin in a reduce block",
+		skipDoc:     true,
+		document:    `[1, 2, 3]`,
+		expression:  `.[] as $x ireduce(0; def add: . + $x; add)`,
+		expected: []string{
+			"D0, P[], (!!int)::6\n",
+		},
+	},
+	{
+		description: "Builtins with a different number of arguments are not replaced",
+		skipDoc:     true,
+		document:    `[1, 2, 3]`,
+		expression:  `def add(f): "mine"; add`,
+		expected: []string{
+			"D0, P[], (!!int)::6\n",
+		},
+	},
+	{
+		description: "Functions with arguments take precedence over builtins",
+		skipDoc:     true,
+		document:    `[1, 2, 3]`,
+		expression:  `def first(f; g): g; first(1; 2), first(.[])`,
+		expected: []string{
+			"D0, P[], (!!int)::2\n",
+			"D0, P[0], (!!int)::1\n",
+		},
+	},
+	{
+		description: "Function parameters take precedence over builtins",
+		skipDoc:     true,
+		document:    `[1, 2, 3]`,
+		expression:  `def apply(add): [add]; apply(length), add`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 3\n",
+			"D0, P[], (!!int)::6\n",
+		},
+	},
+	{
+		description: "Builtins are not replaced outside the scope of the function",
+		skipDoc:     true,
+		document:    `[1, 2, 3]`,
+		expression:  `(def add: "mine"; add), add`,
+		expected: []string{
+			"D0, P[], (!!str)::mine\n",
+			"D0, P[], (!!int)::6\n",
+		},
+	},
+}
+
+func TestFunctionOperatorScenarios(t *testing.T) {
+	for _, tt := range functionOperatorScenarios {
+		testScenario(t, &tt)
+	}
+	documentOperatorScenarios(t, "user-defined-functions", functionOperatorScenarios)
+}
@@ -0,0 +1,480 @@
+package yqlib
+
+import (
+	"container/list"
+	"fmt"
+	"strconv"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// emitFunction is given each result of a generator in turn, it returns true to stop the generator.
+type emitFunction func(candidate *CandidateNode) (bool, error)
+
+// generate passes each result of the expression to emit, stopping as soon as emit asks it to.
+// Recursive descent, recurse, pipes, unions, range and repeat are generated lazily, so that limit, first
+// and nth can stop early (e.g. limit(1; ..) does not traverse the whole document). All other
+// expressions are evaluated as normal before their results are emitted.
+func generate(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode, emit emitFunction) (bool, error) {
+	switch expressionNode.Operation.OperationType {
+	case recursiveDescentOpType, recurseOpType:
+		preferences := expressionNode.Operation.Preferences.(recursiveDescentPreferences)
+		return generateRecursiveDescent(context, preferences, emit)
+	case pipeOpType, shortPipeOpType:
+		if !isLazyGenerator(expressionNode.LHS) {
+			// the lhs may set variables, so evaluate it as usual and pass its context on
+			lhs, err := d.GetMatchingNodes(context, expressionNode.LHS)
+			if err != nil {
+				return false, err
+			}
+			return generate(d, lhs, expressionNode.RHS, emit)
+		}
+		return generate(d, context, expressionNode.LHS, func(candidate *CandidateNode) (bool, error) {
+			return generate(d, context.SingleChildContext(candidate), expressionNode.RHS, emit)
+		})
+	case unionOpType:
+		stopped, err := generate(d, context, expressionNode.LHS, emit)
+		if stopped || err != nil {
+			return stopped, err
+		}
+		return generate(d, context, expressionNode.RHS, emit)
+	case rangeOpType:
+		return generateRange(d, context, expressionNode, emit)
+	case repeatOpType:
+		return generateRepeat(d, context, expressionNode, emit)
+	case recurseWithArgsOpType:
+		return generateRecurse(d, context, expressionNode, emit)
+	}
+
+	results, err := d.GetMatchingNodes(context, expressionNode)
+	if err != nil {
+		return false, err
+	}
+	return emitAll(results.MatchingNodes, emit)
+}
+
+func isLazyGenerator(expressionNode *ExpressionNode) bool {
+	switch expressionNode.Operation.OperationType {
+	case recursiveDescentOpType, recurseOpType, rangeOpType, repeatOpType, recurseWithArgsOpType:
+		return true
+	case pipeOpType, shortPipeOpType, unionOpType:
+		return isLazyGenerator(expressionNode.LHS) && isLazyGenerator(expressionNode.RHS)
+	}
+	return false
+}
+
+func emitAll(candidates *list.List, emit emitFunction) (bool, error) {
+	for el := candidates.Front(); el != nil; el = el.Next() {
+		stopped, err := emit(el.Value.(*CandidateNode))
+		if stopped || err != nil {
+			return stopped, err
+		}
+	}
+	return false, nil
+}
+
+// collectResults runs a generator to completion, returning all of its results.
+func collectResults(generator func(emit emitFunction) (bool, error)) (*list.List, error) {
+	results := list.New()
+	_, err := generator(func(candidate *CandidateNode) (bool, error) {
+		results.PushBack(candidate)
+		return false, nil
+	})
+	return results, err
+}
+
+func generateRecursiveDescent(context Context, preferences recursiveDescentPreferences, emit emitFunction) (bool, error) {
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		candidate.Node = unwrapDoc(candidate.Node)
+
+		stopped, err := emit(candidate)
+		if stopped || err != nil {
+			return stopped, err
+		}
+
+		if candidate.Node.Kind != yaml.AliasNode && len(candidate.Node.Content) > 0 &&
+			(preferences.RecurseArray || candidate.Node.Kind != yaml.SequenceNode) {
+
+			children, err := splat(context.SingleChildContext(candidate), preferences.TraversePreferences)
+			if err != nil {
+				return false, err
+			}
+			stopped, err = generateRecursiveDescent(children, preferences, emit)
+			if stopped || err != nil {
+				return stopped, err
+			}
+		}
+	}
+	return false, nil
+}
+
+// getCountArgument evaluates the expression against the candidate, expecting a single integer.
+func getCountArgument(d *dataTreeNavigator, context Context, candidate *CandidateNode, operation string, expressionNode *ExpressionNode) (int, error) {
+	result, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(candidate), expressionNode)
+	if err != nil {
+		return 0, err
+	}
+	if result.MatchingNodes.Len() != 1 {
+		return 0, fmt.Errorf("%v expects a single number, but got %v results", operation, result.MatchingNodes.Len())
+	}
+	return getIntArgument(operation, unwrapDoc(result.MatchingNodes.Front().Value.(*CandidateNode).Node))
+}
+
+func limitOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- limitOperator")
+	args := getFunctionArguments(expressionNode.RHS)
+	if len(args) != 2 {
+		return Context{}, fmt.Errorf("limit must be given a count and an expression, e.g. limit(3; .[])")
+	}
+
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		count, err := getCountArgument(d, context, candidate, "limit", args[0])
+		if err != nil {
+			return Context{}, err
+		}
+		if count <= 0 {
+			continue
+		}
+
+		found := 0
+		_, err = generate(d, context.SingleReadonlyChildContext(candidate), args[1], func(result *CandidateNode) (bool, error) {
+			results.PushBack(result)
+			found = found + 1
+			return found >= count, nil
+		})
+		if err != nil {
+			return Context{}, err
+		}
+	}
+
+	return context.ChildContext(results), nil
+}
+
+type arrayElementPreferences struct {
+	Last bool
+}
+
+// first and last (without arguments) are short hand for .[0] and .[-1]
+func arrayElementOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	preferences := expressionNode.Operation.Preferences.(arrayElementPreferences)
+	operation := "first"
+	if preferences.Last {
+		operation = "last"
+	}
+	log.Debugf("-- arrayElementOperator %v", operation)
+
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		node := unwrapDoc(candidate.Node)
+		if node.Kind != yaml.SequenceNode {
+			return Context{}, fmt.Errorf("%v only works with arrays, got %v (%v)", operation, node.Tag, candidate.GetNicePath())
+		}
+		if len(node.Content) == 0 {
+			results.PushBack(candidate.CreateReplacement(createScalarNode(nil, "null")))
+			continue
+		}
+		index := 0
+		if preferences.Last {
+			index = len(node.Content) - 1
+		}
+		results.PushBack(candidate.CreateChildInArray(index, node.Content[index]))
+	}
+
+	return context.ChildContext(results), nil
+}
+
+func firstWithArgsOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- firstWithArgsOperator")
+
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		_, err := generate(d, context.SingleReadonlyChildContext(candidate), expressionNode.RHS, func(result *CandidateNode) (bool, error) {
+			results.PushBack(result)
+			return true, nil
+		})
+		if err != nil {
+			return Context{}, err
+		}
+	}
+
+	return context.ChildContext(results), nil
+}
+
+func lastWithArgsOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- lastWithArgsOperator")
+
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		found, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(candidate), expressionNode.RHS)
+		if err != nil {
+			return Context{}, err
+		}
+		if found.MatchingNodes.Len() > 0 {
+			results.PushBack(found.MatchingNodes.Back().Value)
+		}
+	}
+
+	return context.ChildContext(results), nil
+}
+
+// nth(n) is short hand for .[n], nth(n; f) returns the nth result of f.
+func nthOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- nthOperator")
+	args := getFunctionArguments(expressionNode.RHS)
+	if len(args) > 2 {
+		return Context{}, fmt.Errorf("nth must be given an index and optionally an expression, e.g. nth(2; .[])")
+	}
+
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		index, err := getCountArgument(d, context, candidate, "nth", args[0])
+		if err != nil {
+			return Context{}, err
+		}
+		if index < 0 {
+			return Context{}, fmt.Errorf("nth does not support negative indices, got %v", index)
+		}
+
+		if len(args) == 1 {
+			node := unwrapDoc(candidate.Node)
+			if node.Kind != yaml.SequenceNode {
+				return Context{}, fmt.Errorf("nth only works with arrays, got %v (%v)", node.Tag, candidate.GetNicePath())
+			}
+			if index < len(node.Content) {
+				results.PushBack(candidate.CreateChildInArray(index, node.Content[index]))
+			} else {
+				results.PushBack(candidate.CreateReplacement(createScalarNode(nil, "null")))
+			}
+			continue
+		}
+
+		current := 0
+		_, err = generate(d, context.SingleReadonlyChildContext(candidate), args[1], func(result *CandidateNode) (bool, error) {
+			if current == index {
+				results.PushBack(result)
+				return true, nil
+			}
+			current = current + 1
+			return false, nil
+		})
+		if err != nil {
+			return Context{}, err
+		}
+	}
+
+	return context.ChildContext(results), nil
+}
+
+func rangeOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- rangeOperator")
+	results, err := collectResults(func(emit emitFunction) (bool, error) {
+		return generateRange(d, context, expressionNode, emit)
+	})
+	if err != nil {
+		return Context{}, err
+	}
+	return context.ChildContext(results), nil
+}
+
+// range(upto), range(from; upto) and range(from; upto; by)
+func generateRange(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode, emit emitFunction) (bool, error) {
+	args := getFunctionArguments(expressionNode.RHS)
+	if len(args) > 3 {
+		return false, fmt.Errorf("range must be given at most 3 arguments, e.g. range(from; upto; by)")
+	}
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+
+		numbers := make([]*mathNumber, len(args))
+		for i, arg := range args {
+			result, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(candidate), arg)
+			if err != nil {
+				return false, err
+			}
+			if result.MatchingNodes.Len() != 1 {
+				return false, fmt.Errorf("range expects each argument to be a single number, but got %v results", result.MatchingNodes.Len())
+			}
+			numbers[i], err = parseMathNumber("range", result.MatchingNodes.Front().Value.(*CandidateNode))
+			if err != nil {
+				return false, err
+			}
+		}
+
+		from, upto, by := 0.0, 0.0, 1.0
+		allInts := true
+		switch len(numbers) {
+		case 1:
+			upto = numbers[0].floatValue
+		case 2, 3:
+			from, upto = numbers[0].floatValue, numbers[1].floatValue
+			if len(numbers) == 3 {
+				by = numbers[2].floatValue
+			}
+		}
+		for _, number := range numbers {
+			allInts = allInts && number.isInt
+		}
+		if by == 0 {
+			return false, fmt.Errorf("range step cannot be zero")
+		}
+
+		for value := from; (by > 0 && value < upto) || (by < 0 && value > upto); value = value + by {
+			var node *yaml.Node
+			if allInts {
+				node = createScalarNode(int64(value), strconv.FormatInt(int64(value), 10))
+			} else {
+				node = createScalarNode(value, fmt.Sprintf("%v", value))
+			}
+			stopped, err := emit(candidate.CreateReplacement(node))
+			if stopped || err != nil {
+				return stopped, err
+			}
+		}
+	}
+	return false, nil
+}
+
+// until(cond; next) applies next until cond is true.
+func untilOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- untilOperator")
+	args := getFunctionArguments(expressionNode.RHS)
+	if len(args) != 2 {
+		return Context{}, fmt.Errorf("until must be given a condition and an update, e.g. until(. > 100; . * 2)")
+	}
+
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		pending := []*CandidateNode{el.Value.(*CandidateNode)}
+
+		for len(pending) > 0 {
+			current := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+
+			done, err := matchesFilter(d, context.SingleReadonlyChildContext(current), args[0])
+			if err != nil {
+				return Context{}, err
+			}
+			if done {
+				results.PushBack(current)
+				continue
+			}
+
+			next, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(current), args[1])
+			if err != nil {
+				return Context{}, err
+			}
+			// push in reverse, so that results come out in order
+			for nextEl := next.MatchingNodes.Back(); nextEl != nil; nextEl = nextEl.Prev() {
+				pending = append(pending, nextEl.Value.(*CandidateNode))
+			}
+		}
+	}
+
+	return context.ChildContext(results), nil
+}
+
+// repeat(n) on a string repeats the string n times, like it always has. Otherwise repeat(f)
+// outputs the input and then recursively repeats f on each of its results.
+func repeatOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- repeatOperator")
+	results, err := collectResults(func(emit emitFunction) (bool, error) {
+		return generateRepeat(d, context, expressionNode, emit)
+	})
+	if err != nil {
+		return Context{}, err
+	}
+	return context.ChildContext(results), nil
+}
+
+func generateRepeat(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode, emit emitFunction) (bool, error) {
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		node := unwrapDoc(candidate.Node)
+
+		if node.Kind != yaml.ScalarNode || guessTagFromCustomType(node) != "!!str" {
+			stopped, err := generateRepeatFrom(d, context, expressionNode, []*CandidateNode{candidate}, emit)
+			if stopped || err != nil {
+				return stopped, err
+			}
+			continue
+		}
+
+		// strings are repeated when given a number, so the argument needs to be evaluated
+		// up front - its results are reused as the first step of the generator otherwise.
+		next, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(candidate), expressionNode.RHS)
+		if err != nil {
+			return false, err
+		}
+		if next.MatchingNodes.Len() == 1 {
+			countNode := unwrapDoc(next.MatchingNodes.Front().Value.(*CandidateNode).Node)
+			if countNode.Kind == yaml.ScalarNode && guessTagFromCustomType(countNode) == "!!int" {
+				repeated, err := repeatString("repeat", node.Value, countNode)
+				if err != nil {
+					return false, err
+				}
+				stopped, err := emit(candidate.CreateReplacement(repeated))
+				if stopped || err != nil {
+					return stopped, err
+				}
+				continue
+			}
+		}
+
+		stopped, err := emit(candidate)
+		if stopped || err != nil {
+			return stopped, err
+		}
+		var pending []*CandidateNode
+		for nextEl := next.MatchingNodes.Front(); nextEl != nil; nextEl = nextEl.Next() {
+			pending = append(pending, nextEl.Value.(*CandidateNode))
+		}
+		stopped, err = generateRepeatFrom(d, context, expressionNode, pending, emit)
+		if stopped || err != nil {
+			return stopped, err
+		}
+	}
+	return false, nil
+}
+
+// generateRepeatFrom emits each of the candidates in order, each followed by the results of
+// recursively repeating the expression on it.
+func generateRepeatFrom(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode, candidates []*CandidateNode, emit emitFunction) (bool, error) {
+	// a stack, so push in reverse so that results come out in order
+	pending := make([]*CandidateNode, 0, len(candidates))
+	for index := len(candidates) - 1; index >= 0; index-- {
+		pending = append(pending, candidates[index])
+	}
+
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		stopped, err := emit(current)
+		if stopped || err != nil {
+			return stopped, err
+		}
+
+		next, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(current), expressionNode.RHS)
+		if err != nil {
+			return false, err
+		}
+		for nextEl := next.MatchingNodes.Back(); nextEl != nil; nextEl = nextEl.Prev() {
+			pending = append(pending, nextEl.Value.(*CandidateNode))
+		}
+	}
+	return false, nil
+}
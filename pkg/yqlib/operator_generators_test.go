@@ -0,0 +1,245 @@
+package yqlib
+
+import (
+	"testing"
+)
+
+var generatorsOperatorScenarios = []expressionScenario{
+	{
+		description: "Limit the number of results",
+		document:    "[a, b, c, d]",
+		expression:  `[limit(2; .[])]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- a\n- b\n",
+		},
+	},
+	{
+		description:    "Limit recursive descent",
+		subdescription: "Only as much of the document as needed is traversed.",
+		document:       "a:\n  b: cat\nc: dog\n",
+		expression:     `[limit(3; ..)]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- a:\n    b: cat\n  c: dog\n- b: cat\n- cat\n",
+		},
+	},
+	{
+		description:    "Limit an infinite generator",
+		subdescription: "repeat(f) never finishes by itself, but limit stops it early.",
+		skipDoc:        true,
+		expression:     `[limit(5; 1 | repeat(. * 2))]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 1\n- 2\n- 4\n- 8\n- 16\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   "[a, b]",
+		expression: `[limit(0; .[])]`,
+		expected: []string{
+			"D0, P[], (!!seq)::[]\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		document:      "[a, b]",
+		expression:    `limit(1)`,
+		expectedError: "limit must be given a count and an expression, e.g. limit(3; .[])",
+	},
+	{
+		description: "First element of an array",
+		document:    "[cat, dog, frog]",
+		expression:  `first`,
+		expected: []string{
+			"D0, P[0], (!!str)::cat\n",
+		},
+	},
+	{
+		description: "Last element of an array",
+		document:    "[cat, dog, frog]",
+		expression:  `last`,
+		expected: []string{
+			"D0, P[2], (!!str)::frog\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   "[]",
+		expression: `first`,
+		expected: []string{
+			"D0, P[], (!!null)::null\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		document:      "a: cat",
+		expression:    `last`,
+		expectedError: "last only works with arrays, got !!map ()",
+	},
+	{
+		description: "First result of an expression",
+		document:    "[{name: a, ok: false}, {name: b, ok: true}, {name: c, ok: true}]",
+		expression:  `first(.[] | select(.ok)) | .name`,
+		expected: []string{
+			"D0, P[1 name], (!!str)::b\n",
+		},
+	},
+	{
+		description: "Last result of an expression",
+		document:    "[{name: a, ok: false}, {name: b, ok: true}, {name: c, ok: true}]",
+		expression:  `last(.[] | select(.ok)) | .name`,
+		expected: []string{
+			"D0, P[2 name], (!!str)::c\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `first(1 | repeat(. + 1))`,
+		expected: []string{
+			"D0, P[], (!!int)::1\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   "[a, b]",
+		expression: `[last(.[] | select(. == "z"))]`,
+		expected: []string{
+			"D0, P[], (!!seq)::[]\n",
+		},
+	},
+	{
+		description: "Nth element of an array",
+		document:    "[cat, dog, frog]",
+		expression:  `nth(1)`,
+		expected: []string{
+			"D0, P[1], (!!str)::dog\n",
+		},
+	},
+	{
+		description:    "Nth result of an expression",
+		subdescription: "Indices start at 0.",
+		document:       "[1, 2, 3, 4, 5, 6]",
+		expression:     `nth(1; .[] | select(. % 2 == 0))`,
+		expected: []string{
+			"D0, P[3], (!!int)::4\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   "[cat]",
+		expression: `nth(5)`,
+		expected: []string{
+			"D0, P[], (!!null)::null\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		document:      "[cat]",
+		expression:    `nth(-1; .[])`,
+		expectedError: "nth does not support negative indices, got -1",
+	},
+	{
+		description: "Range up to a number",
+		expression:  `[range(5)]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 0\n- 1\n- 2\n- 3\n- 4\n",
+		},
+	},
+	{
+		description: "Range between two numbers",
+		expression:  `[range(2; 5)]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 2\n- 3\n- 4\n",
+		},
+	},
+	{
+		description: "Range with a step",
+		expression:  `[range(0; 10; 3)]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 0\n- 3\n- 6\n- 9\n",
+		},
+	},
+	{
+		description: "Range counting down",
+		expression:  `[range(5; 0; -2)]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 5\n- 3\n- 1\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `[range(0; 1; 0.25)]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- !!float 0\n- 0.25\n- 0.5\n- 0.75\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		expression:    `range(0; 10; 0)`,
+		expectedError: "range step cannot be zero",
+	},
+	{
+		description:    "Pad an array using range",
+		subdescription: "Makes sure the array has at least 5 elements.",
+		document:       "[a, b]",
+		expression:     `.[range(length; 5)] |= "pad"`,
+		expected: []string{
+			"D0, P[], (doc)::[a, b, pad, pad, pad]\n",
+		},
+	},
+	{
+		description: "Apply an update until a condition is met",
+		expression:  `1 | until(. > 100; . * 2)`,
+		expected: []string{
+			"D0, P[], (!!int)::128\n",
+		},
+	},
+	{
+		description:    "Repeat an expression",
+		subdescription: "Outputs the input, then recursively repeats the expression against its results until it returns nothing.",
+		expression:     `[10 | repeat(select(. > 1) | . / 2 | floor)]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 10\n- 5\n- 2\n- 1\n",
+		},
+	},
+	{
+		description:    "Repeat a string",
+		subdescription: "Given a string and a number, repeat repeats the string.",
+		expression:     `"ab" | repeat(3)`,
+		expected: []string{
+			"D0, P[], (!!str)::ababab\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `[limit(2; "ab" | repeat(3))]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- ababab\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `[limit(3; "a" | repeat(. + "b"))]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- a\n- ab\n- abb\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `[limit(4; "a" | repeat(. + "b", . + "c"))]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- a\n- ab\n- abb\n- abbb\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		expression:    `"ab" | repeat(-1)`,
+		expectedError: "repeat expects a positive number, got -1",
+	},
+}
+
+func TestGeneratorsOperatorScenarios(t *testing.T) {
+	for _, tt := range generatorsOperatorScenarios {
+		testScenario(t, &tt)
+	}
+	documentOperatorScenarios(t, "generators", generatorsOperatorScenarios)
+}
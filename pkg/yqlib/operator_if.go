@@ -0,0 +1,51 @@
+package yqlib
+
+import (
+	"container/list"
+	"fmt"
+)
+
+func ifOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- ifOperator")
+	// if <cond> then <a> else <b> end
+	// is tokenised as
+	// (<cond>) IF ((<a>) BLOCK (<b>))
+
+	if expressionNode.RHS.Operation.OperationType != blockOpType {
+		return Context{}, fmt.Errorf("if must be given a then and else block, got %v instead", expressionNode.RHS.Operation.OperationType.Type)
+	}
+
+	thenExp := expressionNode.RHS.LHS
+	elseExp := expressionNode.RHS.RHS
+
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+
+		condition, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(candidate), expressionNode.LHS)
+		if err != nil {
+			return Context{}, err
+		}
+
+		// like jq, each result of the condition produces a branch evaluation
+		for conditionEl := condition.MatchingNodes.Front(); conditionEl != nil; conditionEl = conditionEl.Next() {
+			truthy, err := isTruthy(conditionEl.Value.(*CandidateNode))
+			if err != nil {
+				return Context{}, err
+			}
+			branchExp := elseExp
+			if truthy {
+				branchExp = thenExp
+			}
+
+			branch, err := d.GetMatchingNodes(context.SingleChildContext(candidate), branchExp)
+			if err != nil {
+				return Context{}, err
+			}
+			results.PushBackList(branch.MatchingNodes)
+		}
+	}
+
+	return context.ChildContext(results), nil
+}
@@ -0,0 +1,96 @@
+package yqlib
+
+import (
+	"testing"
+)
+
+var ifOperatorScenarios = []expressionScenario{
+	{
+		description: "if then else",
+		document:    `[1, 2, 3]`,
+		expression:  `.[] |= if . == 1 then "one" else "other" end`,
+		expected: []string{
+			"D0, P[], (doc)::[one, other, other]\n",
+		},
+	},
+	{
+		description: "if then elif else",
+		document:    `[1, 2, 3]`,
+		expression:  `.[] | if . == 1 then "one" elif . == 2 then "two" else "many" end`,
+		expected: []string{
+			"D0, P[], (!!str)::one\n",
+			"D0, P[], (!!str)::two\n",
+			"D0, P[], (!!str)::many\n",
+		},
+	},
+	{
+		description:    "if without else",
+		subdescription: "When there is no else branch, the node is returned unchanged",
+		document:       `[{name: cat, legs: 4}, {name: snake}]`,
+		expression:     `.[] |= if has("legs") then .name += " has legs" end`,
+		expected: []string{
+			"D0, P[], (doc)::[{name: cat has legs, legs: 4}, {name: snake}]\n",
+		},
+	},
+	{
+		description:    "Conditionally pick what to update",
+		subdescription: "Conditionals can be used on the LHS of assignment operators",
+		document:       `{useProd: true, dev: {replicas: 1}, prod: {replicas: 3}}`,
+		expression:     `(if .useProd then .prod else .dev end).replicas += 1`,
+		expected: []string{
+			"D0, P[], (doc)::{useProd: true, dev: {replicas: 1}, prod: {replicas: 4}}\n",
+		},
+	},
+	{
+		description: "Nested conditionals",
+		document:    `[{a: 1, b: 1}, {a: 1, b: 2}, {a: 2}]`,
+		expression:  `[.[] | if .a == 1 then if .b == 1 then "both" else "a only" end else "neither" end]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- both\n- a only\n- neither\n",
+		},
+	},
+	{
+		description: "Conditional on the RHS of an assignment",
+		skipDoc:     true,
+		document:    `{a: 5, b: cat}`,
+		expression:  `.b = if .a > 3 then "big" else "small" end`,
+		expected: []string{
+			"D0, P[], (doc)::{a: 5, b: big}\n",
+		},
+	},
+	{
+		description: "Condition with multiple results",
+		skipDoc:     true,
+		document:    `{a: 5}`,
+		expression:  `if (true, false) then "yes" else "no" end`,
+		expected: []string{
+			"D0, P[], (!!str)::yes\n",
+			"D0, P[], (!!str)::no\n",
+		},
+	},
+	{
+		description: "Condition with no results",
+		skipDoc:     true,
+		document:    `{a: 5}`,
+		expression:  `[if .a[] then "yes" else "no" end]`,
+		expected: []string{
+			"D0, P[], (!!seq)::[]\n",
+		},
+	},
+	{
+		description: "Piped conditional",
+		skipDoc:     true,
+		document:    `{a: 5}`,
+		expression:  `if .a then .a else 0 end | . + 1`,
+		expected: []string{
+			"D0, P[a], (!!int)::6\n",
+		},
+	},
+}
+
+func TestIfOperatorScenarios(t *testing.T) {
+	for _, tt := range ifOperatorScenarios {
+		testScenario(t, &tt)
+	}
+	documentOperatorScenarios(t, "conditionals", ifOperatorScenarios)
+}
@@ -0,0 +1,169 @@
+package yqlib
+
+import (
+	"container/list"
+	"fmt"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+type joinRow struct {
+	key    string
+	hasKey bool
+	node   *yaml.Node
+}
+
+type joinIndex struct {
+	rows    []joinRow
+	matches map[string][]*yaml.Node
+}
+
+// hash_join(source; left_key; right_key) and hash_join(source; left_key; right_key; mode)
+// join each element of the input array with the rows of the source that have the same key,
+// returning [left, right] pairs. The source is indexed once, so this is O(n + m).
+func hashJoinOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- hashJoinOperator")
+	args := getFunctionArguments(expressionNode.RHS)
+	if len(args) != 3 && len(args) != 4 {
+		return Context{}, fmt.Errorf("hash_join must be given a source, a left key, a right key and optionally a mode, e.g. hash_join($owners; .team; .name; \"left\")")
+	}
+
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		leftNode := unwrapDoc(candidate.Node)
+		if leftNode.Kind != yaml.SequenceNode {
+			return Context{}, fmt.Errorf("hash_join only works with arrays, got %v (%v)", leftNode.Tag, candidate.GetNicePath())
+		}
+
+		mode := "inner"
+		if len(args) == 4 {
+			modeNode, err := getSingleArgument(d, context, candidate, "hash_join", args[3])
+			if err != nil {
+				return Context{}, err
+			}
+			mode = modeNode.Value
+		}
+		if mode != "inner" && mode != "left" && mode != "full" {
+			return Context{}, fmt.Errorf("hash_join mode must be one of inner, left or full, got %v", mode)
+		}
+
+		index, err := createJoinIndex(d, context, candidate, args[0], args[2])
+		if err != nil {
+			return Context{}, err
+		}
+
+		joined, err := hashJoin(d, context, leftNode, index, args[1], mode)
+		if err != nil {
+			return Context{}, err
+		}
+		results.PushBack(candidate.CreateReplacement(joined))
+	}
+
+	return context.ChildContext(results), nil
+}
+
+func getSingleArgument(d *dataTreeNavigator, context Context, candidate *CandidateNode, operation string, expressionNode *ExpressionNode) (*yaml.Node, error) {
+	result, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(candidate), expressionNode)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchingNodes.Len() != 1 {
+		return nil, fmt.Errorf("%v expects a single value, but got %v results", operation, result.MatchingNodes.Len())
+	}
+	return unwrapDoc(result.MatchingNodes.Front().Value.(*CandidateNode).Node), nil
+}
+
+// getJoinKey returns the canonical encoding of the key, and false if there is no
+// key (or it is null) - like SQL, null keys never match.
+func getJoinKey(d *dataTreeNavigator, context Context, node *yaml.Node, keyExpression *ExpressionNode) (string, bool, error) {
+	result, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(&CandidateNode{Node: node}), keyExpression)
+	if err != nil {
+		return "", false, err
+	}
+	if result.MatchingNodes.Len() == 0 {
+		return "", false, nil
+	}
+	keyNode := resolveAlias(unwrapDoc(result.MatchingNodes.Front().Value.(*CandidateNode).Node))
+	if keyNode.Tag == "!!null" {
+		return "", false, nil
+	}
+	key, err := canonicalEncoding(keyNode)
+	return key, true, err
+}
+
+// createJoinIndex evaluates the source, and indexes its rows by key. Arrays in the
+// source have each of their elements indexed, so both $owners and $owners[] work.
+func createJoinIndex(d *dataTreeNavigator, context Context, candidate *CandidateNode, source *ExpressionNode, keyExpression *ExpressionNode) (*joinIndex, error) {
+	sources, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(candidate), source)
+	if err != nil {
+		return nil, err
+	}
+
+	index := &joinIndex{matches: map[string][]*yaml.Node{}}
+
+	for el := sources.MatchingNodes.Front(); el != nil; el = el.Next() {
+		sourceNode := resolveAlias(unwrapDoc(el.Value.(*CandidateNode).Node))
+		rows := []*yaml.Node{sourceNode}
+		if sourceNode.Kind == yaml.SequenceNode {
+			rows = sourceNode.Content
+		}
+
+		for _, row := range rows {
+			key, hasKey, err := getJoinKey(d, context, row, keyExpression)
+			if err != nil {
+				return nil, err
+			}
+			// rows without a key are kept for full joins, they just never match
+			index.rows = append(index.rows, joinRow{key: key, hasKey: hasKey, node: row})
+			if hasKey {
+				index.matches[key] = append(index.matches[key], row)
+			}
+		}
+	}
+	return index, nil
+}
+
+func createJoinPair(left *yaml.Node, right *yaml.Node) *yaml.Node {
+	if left == nil {
+		left = createScalarNode(nil, "null")
+	}
+	if right == nil {
+		right = createScalarNode(nil, "null")
+	}
+	return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{left, right}}
+}
+
+func hashJoin(d *dataTreeNavigator, context Context, leftNode *yaml.Node, index *joinIndex, keyExpression *ExpressionNode, mode string) (*yaml.Node, error) {
+	joined := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	matchedKeys := map[string]bool{}
+
+	for _, left := range leftNode.Content {
+		key, hasKey, err := getJoinKey(d, context, left, keyExpression)
+		if err != nil {
+			return nil, err
+		}
+		rights := []*yaml.Node{}
+		if hasKey {
+			rights = index.matches[key]
+			matchedKeys[key] = true
+		}
+
+		for _, right := range rights {
+			joined.Content = append(joined.Content, createJoinPair(left, right))
+		}
+		if len(rights) == 0 && mode != "inner" {
+			joined.Content = append(joined.Content, createJoinPair(left, nil))
+		}
+	}
+
+	if mode == "full" {
+		for _, row := range index.rows {
+			if !row.hasKey || !matchedKeys[row.key] {
+				joined.Content = append(joined.Content, createJoinPair(nil, row.node))
+			}
+		}
+	}
+	return joined, nil
+}
@@ -0,0 +1,112 @@
+package yqlib
+
+import (
+	"testing"
+)
+
+var joinDocument = `services:
+  - {name: web, team: a}
+  - {name: db, team: b}
+owners:
+  - {team: a, lead: alice}
+  - {team: c, lead: carol}
+`
+
+var joinOperatorScenarios = []expressionScenario{
+	{
+		description:    "Join two arrays",
+		subdescription: "Returns a [left, right] pair for each match. This is an inner join, so elements without a match are dropped.",
+		document:       joinDocument,
+		expression:     `.owners as $owners | .services | hash_join($owners; .team; .team)`,
+		expected: []string{
+			"D0, P[services], (!!seq)::- - {name: web, team: a}\n  - {team: a, lead: alice}\n",
+		},
+	},
+	{
+		description:    "Merge the joined pairs",
+		subdescription: "`lookup` is another name for `hash_join`.",
+		document:       joinDocument,
+		expression:     `.owners as $owners | .services | lookup($owners; .team; .team) | map(.[0] * .[1])`,
+		expected: []string{
+			"D0, P[], (!!seq)::- {name: web, team: a, lead: alice}\n",
+		},
+	},
+	{
+		description:    "Left join",
+		subdescription: "Elements of the input without a match are paired with null.",
+		document:       joinDocument,
+		expression:     `.owners as $owners | .services | hash_join($owners; .team; .team; "left")`,
+		expected: []string{
+			"D0, P[services], (!!seq)::- - {name: web, team: a}\n  - {team: a, lead: alice}\n- - {name: db, team: b}\n  - null\n",
+		},
+	},
+	{
+		description:    "Full outer join",
+		subdescription: "As well as a left join, rows of the source without a match are paired with null at the end.",
+		document:       joinDocument,
+		expression:     `.owners as $owners | .services | hash_join($owners; .team; .team; "full")`,
+		expected: []string{
+			"D0, P[services], (!!seq)::- - {name: web, team: a}\n  - {team: a, lead: alice}\n- - {name: db, team: b}\n  - null\n- - null\n  - {team: c, lead: carol}\n",
+		},
+	},
+	{
+		description:    "Join arrays from different files",
+		subdescription: "Use eval-all (`ea`) to load all the files, and a variable to hold the source.",
+		document:       "services:\n  - {name: web, team: a}\n",
+		document2:      "owners:\n  - {team: a, lead: alice}\n",
+		expression:     `(select(fileIndex == 1) | .owners) as $owners | select(fileIndex == 0) | .services | hash_join($owners; .team; .team) | map(.[0] * .[1])`,
+		expected: []string{
+			"D0, P[], (!!seq)::- {name: web, team: a, lead: alice}\n",
+		},
+	},
+	{
+		description:    "Join on different keys",
+		subdescription: "Every result of the source is used, elements of arrays are used as rows.",
+		document:       "services: [{name: web, owner: a}]\n---\n{id: a, lead: alice}\n---\n{id: b, lead: bob}\n",
+		expression:     `[select(documentIndex > 0)] as $owners | select(documentIndex == 0) | .services | hash_join($owners[]; .owner; .id)`,
+		expected: []string{
+			"D0, P[services], (!!seq)::- - {name: web, owner: a}\n  - {id: a, lead: alice}\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   "a: [{k: 1}, {k: \"1\"}, {k: null}, {x: 1}]\nb: [{k: 1}, {k: null}]\n",
+		expression: `.b as $b | .a | hash_join($b; .k; .k; "left") | map(.[1] | tag)`,
+		expected: []string{
+			"D0, P[], (!!seq)::- '!!map'\n- '!!null'\n- '!!null'\n- '!!null'\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   "a: [{k: {x: 1, y: 2}}]\nb: [{k: {y: 2, x: 1}, v: found}]\n",
+		expression: `.b as $b | .a | hash_join($b; .k; .k) | .[0][1].v`,
+		expected: []string{
+			"D0, P[a 0 1 v], (!!str)::found\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		document:      "a: cat",
+		expression:    `hash_join(.a; .k; .k)`,
+		expectedError: "hash_join only works with arrays, got !!map ()",
+	},
+	{
+		skipDoc:       true,
+		document:      "[]",
+		expression:    `hash_join(.; .k; .k; "outer")`,
+		expectedError: "hash_join mode must be one of inner, left or full, got outer",
+	},
+	{
+		skipDoc:       true,
+		document:      "[]",
+		expression:    `hash_join(.; .k)`,
+		expectedError: "hash_join must be given a source, a left key, a right key and optionally a mode, e.g. hash_join($owners; .team; .name; \"left\")",
+	},
+}
+
+func TestJoinOperatorScenarios(t *testing.T) {
+	for _, tt := range joinOperatorScenarios {
+		testScenario(t, &tt)
+	}
+	documentOperatorScenarios(t, "join", joinOperatorScenarios)
+}
@@ -0,0 +1,187 @@
+package yqlib
+
+import (
+	"container/list"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// mathNumber is a parsed numeric scalar, remembering enough about the
+// original node (tag, style, int format) to create results that look like it.
+type mathNumber struct {
+	node       *yaml.Node
+	isCustom   bool
+	isInt      bool
+	intFormat  string
+	intValue   int64
+	floatValue float64
+}
+
+func parseMathNumber(operation string, candidate *CandidateNode) (*mathNumber, error) {
+	node := unwrapDoc(candidate.Node)
+	tag := node.Tag
+	isCustom := false
+	if !strings.HasPrefix(tag, "!!") {
+		// custom tag - we have to have a guess
+		tag = guessTagFromCustomType(node)
+		isCustom = true
+	}
+	if node.Kind != yaml.ScalarNode || (tag != "!!int" && tag != "!!float") {
+		return nil, fmt.Errorf("%v only works with numbers, got %v (%v)", operation, node.Tag, candidate.GetNicePath())
+	}
+
+	number := &mathNumber{node: node, isCustom: isCustom, isInt: tag == "!!int"}
+	if number.isInt {
+		format, value, err := parseInt64(node.Value)
+		if err != nil {
+			return nil, err
+		}
+		number.intFormat = format
+		number.intValue = value
+		number.floatValue = float64(value)
+		return number, nil
+	}
+	value, err := strconv.ParseFloat(node.Value, 64)
+	if err != nil {
+		return nil, err
+	}
+	number.floatValue = value
+	return number, nil
+}
+
+func (n *mathNumber) createIntNode(value int64) *yaml.Node {
+	format := n.intFormat
+	tag := n.node.Tag
+	if !n.isInt {
+		format = "%v"
+		if !n.isCustom {
+			tag = "!!int"
+		}
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Style: n.node.Style, Value: fmt.Sprintf(format, value)}
+}
+
+func (n *mathNumber) createFloatNode(value float64) *yaml.Node {
+	tag := n.node.Tag
+	if !n.isCustom {
+		tag = "!!float"
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Style: n.node.Style, Value: fmt.Sprintf("%v", value)}
+}
+
+// createIntegralNode is used for functions like floor that produce whole numbers,
+// the result is an int unless it is too big to fit into one.
+func (n *mathNumber) createIntegralNode(value float64) *yaml.Node {
+	if value >= math.MinInt64 && value < math.MaxInt64 {
+		return n.createIntNode(int64(value))
+	}
+	return n.createFloatNode(value)
+}
+
+type mathFunction func(number *mathNumber) (*yaml.Node, error)
+
+func mathOperator(context Context, operation string, calculation mathFunction) (Context, error) {
+	log.Debugf("-- mathOperator %v", operation)
+	results := list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		number, err := parseMathNumber(operation, candidate)
+		if err != nil {
+			return Context{}, err
+		}
+		result, err := calculation(number)
+		if err != nil {
+			return Context{}, fmt.Errorf("%w (%v)", err, candidate.GetNicePath())
+		}
+		results.PushBack(candidate.CreateReplacement(result))
+	}
+
+	return context.ChildContext(results), nil
+}
+
+func roundingFunction(round func(float64) float64) mathFunction {
+	return func(number *mathNumber) (*yaml.Node, error) {
+		if number.isInt {
+			return number.createIntNode(number.intValue), nil
+		}
+		return number.createIntegralNode(round(number.floatValue)), nil
+	}
+}
+
+func floorOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	return mathOperator(context, "floor", roundingFunction(math.Floor))
+}
+
+func ceilOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	return mathOperator(context, "ceil", roundingFunction(math.Ceil))
+}
+
+func roundOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	return mathOperator(context, "round", roundingFunction(math.Round))
+}
+
+func absOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	return mathOperator(context, "abs", func(number *mathNumber) (*yaml.Node, error) {
+		if number.isInt {
+			if number.intValue < 0 {
+				return number.createIntNode(-number.intValue), nil
+			}
+			return number.createIntNode(number.intValue), nil
+		}
+		return number.createFloatNode(math.Abs(number.floatValue)), nil
+	})
+}
+
+func sqrtOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	return mathOperator(context, "sqrt", func(number *mathNumber) (*yaml.Node, error) {
+		if number.floatValue < 0 {
+			return nil, fmt.Errorf("cannot take the square root of a negative number %v", number.node.Value)
+		}
+		return number.createFloatNode(math.Sqrt(number.floatValue)), nil
+	})
+}
+
+func logOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	return mathOperator(context, "log", func(number *mathNumber) (*yaml.Node, error) {
+		if number.floatValue <= 0 {
+			return nil, fmt.Errorf("cannot take the log of a number that is not positive %v", number.node.Value)
+		}
+		return number.createFloatNode(math.Log(number.floatValue)), nil
+	})
+}
+
+func powOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- powOperator")
+	if expressionNode.RHS == nil || expressionNode.RHS.Operation.OperationType != blockOpType {
+		return Context{}, fmt.Errorf("pow must be given a base and an exponent, e.g. pow(.a; 2)")
+	}
+	// the block has the base on the LHS and the exponent on the RHS
+	return crossFunction(d, context.ReadOnlyClone(), expressionNode.RHS, pow, false)
+}
+
+func pow(d *dataTreeNavigator, context Context, lhs *CandidateNode, rhs *CandidateNode) (*CandidateNode, error) {
+	base, err := parseMathNumber("pow", lhs)
+	if err != nil {
+		return nil, err
+	}
+	exponent, err := parseMathNumber("pow", rhs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := math.Pow(base.floatValue, exponent.floatValue)
+	if math.IsNaN(result) {
+		return nil, fmt.Errorf("pow(%v; %v) is not a number (%v)", base.node.Value, exponent.node.Value, lhs.GetNicePath())
+	}
+
+	// whole number powers of integers stay integers, as long as they are precise
+	if base.isInt && exponent.isInt && exponent.intValue >= 0 && math.Abs(result) < 1<<53 {
+		return lhs.CreateReplacement(base.createIntNode(int64(result))), nil
+	}
+	return lhs.CreateReplacement(base.createFloatNode(result)), nil
+}
@@ -0,0 +1,131 @@
+package yqlib
+
+import (
+	"testing"
+)
+
+var mathOperatorScenarios = []expressionScenario{
+	{
+		description:    "Floor, ceil and round",
+		subdescription: "These always return integers",
+		document:       `[1.5, -1.5, 2]`,
+		expression:     `[.[] | floor], [.[] | ceil], [.[] | round]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 1\n- -2\n- 2\n",
+			"D0, P[], (!!seq)::- 2\n- -1\n- 2\n",
+			"D0, P[], (!!seq)::- 2\n- -2\n- 2\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `2.7 | floor | tag`,
+		expected: []string{
+			"D0, P[], (!!str)::!!int\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `0x1F | round`,
+		expected: []string{
+			"D0, P[], (!!int)::0x1F\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `1e300 | floor | tag`,
+		expected: []string{
+			"D0, P[], (!!str)::!!float\n",
+		},
+	},
+	{
+		description: "Absolute value",
+		document:    `[-3, 4, -2.5]`,
+		expression:  `.[] |= abs`,
+		expected: []string{
+			"D0, P[], (doc)::[3, 4, 2.5]\n",
+		},
+	},
+	{
+		description: "Square root",
+		document:    `a: 16`,
+		expression:  `.a | sqrt`,
+		expected: []string{
+			"D0, P[a], (!!float)::4\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		document:      `a: -16`,
+		expression:    `.a | sqrt`,
+		expectedError: "cannot take the square root of a negative number -16 (a)",
+	},
+	{
+		description:    "Natural logarithm",
+		subdescription: "Combine with division for other bases",
+		expression:     `(1000 | log) / (10 | log) | round`,
+		expected: []string{
+			"D0, P[], (!!int)::3\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		expression:    `0 | log`,
+		expectedError: "cannot take the log of a number that is not positive 0 ()",
+	},
+	{
+		description:    "Power",
+		subdescription: "Integers raised to whole number powers stay integers",
+		document:       `{base: 2, replicas: 3}`,
+		expression:     `pow(.base; .replicas), pow(.replicas; 0.5)`,
+		expected: []string{
+			"D0, P[base], (!!int)::8\n",
+			"D0, P[replicas], (!!float)::1.7320508075688772\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   `[2, 3]`,
+		expression: `.[] |= pow(.; 2)`,
+		expected: []string{
+			"D0, P[], (doc)::[4, 9]\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `pow(2; -1)`,
+		expected: []string{
+			"D0, P[], (!!float)::0.5\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		expression:    `pow(-8; 0.5)`,
+		expectedError: "pow(-8; 0.5) is not a number ()",
+	},
+	{
+		skipDoc:       true,
+		expression:    `pow(2)`,
+		expectedError: "pow must be given a base and an exponent, e.g. pow(.a; 2)",
+	},
+	{
+		skipDoc:       true,
+		document:      `a: cat`,
+		expression:    `.a | floor`,
+		expectedError: "floor only works with numbers, got !!str (a)",
+	},
+	{
+		description: "Custom types: that are really numbers",
+		document:    "a: !horse 2.5",
+		expression:  `.a |= round`,
+		expected: []string{
+			"D0, P[], (doc)::a: !horse 3\n",
+		},
+	},
+}
+
+func TestMathOperatorScenarios(t *testing.T) {
+	for _, tt := range mathOperatorScenarios {
+		testScenario(t, &tt)
+	}
+	documentOperatorScenarios(t, "math", mathOperatorScenarios)
+}
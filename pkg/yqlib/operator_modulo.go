@@ -0,0 +1,93 @@
+package yqlib
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+func createModuloOp(lhs *ExpressionNode, rhs *ExpressionNode) *ExpressionNode {
+	return &ExpressionNode{Operation: &Operation{OperationType: moduloOpType},
+		LHS: lhs,
+		RHS: rhs}
+}
+
+func moduloAssignOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	return compoundAssignFunction(d, context, expressionNode, createModuloOp)
+}
+
+func moduloOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("Modulo operator")
+
+	return crossFunction(d, context.ReadOnlyClone(), expressionNode, modulo, false)
+}
+
+func modulo(d *dataTreeNavigator, context Context, lhs *CandidateNode, rhs *CandidateNode) (*CandidateNode, error) {
+	lhs.Node = unwrapDoc(lhs.Node)
+	rhs.Node = unwrapDoc(rhs.Node)
+
+	if lhs.Node.Kind != yaml.ScalarNode || rhs.Node.Kind != yaml.ScalarNode || lhs.Node.Tag == "!!null" {
+		return nil, fmt.Errorf("cannot calculate the modulo of %v by %v", describeOperand(lhs.Node.Tag, lhs), describeOperand(rhs.Node.Tag, rhs))
+	}
+
+	target := lhs.CreateReplacement(&yaml.Node{})
+	target.Node.Kind = yaml.ScalarNode
+	target.Node.Style = lhs.Node.Style
+	if err := moduloScalars(target, lhs, rhs); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+func moduloScalars(target *CandidateNode, lhsC *CandidateNode, rhsC *CandidateNode) error {
+	lhs := lhsC.Node
+	rhs := rhsC.Node
+	lhsTag := lhs.Tag
+	rhsTag := guessTagFromCustomType(rhs)
+	lhsIsCustom := false
+	if !strings.HasPrefix(lhsTag, "!!") {
+		// custom tag - we have to have a guess
+		lhsTag = guessTagFromCustomType(lhs)
+		lhsIsCustom = true
+	}
+
+	if lhsTag == "!!int" && rhsTag == "!!int" {
+		format, lhsNum, err := parseInt64(lhs.Value)
+		if err != nil {
+			return err
+		}
+		_, rhsNum, err := parseInt64(rhs.Value)
+		if err != nil {
+			return err
+		}
+		if rhsNum == 0 {
+			return fmt.Errorf("cannot calculate the modulo of %v by %v", describeOperand(lhs.Value, lhsC), describeOperand("zero", rhsC))
+		}
+		target.Node.Tag = lhs.Tag
+		target.Node.Value = fmt.Sprintf(format, lhsNum%rhsNum)
+	} else if (lhsTag == "!!int" || lhsTag == "!!float") && (rhsTag == "!!int" || rhsTag == "!!float") {
+		lhsNum, err := strconv.ParseFloat(lhs.Value, 64)
+		if err != nil {
+			return err
+		}
+		rhsNum, err := strconv.ParseFloat(rhs.Value, 64)
+		if err != nil {
+			return err
+		}
+		if rhsNum == 0 {
+			return fmt.Errorf("cannot calculate the modulo of %v by %v", describeOperand(lhs.Value, lhsC), describeOperand("zero", rhsC))
+		}
+		if lhsIsCustom {
+			target.Node.Tag = lhs.Tag
+		} else {
+			target.Node.Tag = "!!float"
+		}
+		target.Node.Value = fmt.Sprintf("%v", math.Mod(lhsNum, rhsNum))
+	} else {
+		return fmt.Errorf("cannot calculate the modulo of %v by %v", describeOperand(lhs.Tag, lhsC), describeOperand(rhs.Tag, rhsC))
+	}
+	return nil
+}
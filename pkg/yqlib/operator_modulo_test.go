@@ -0,0 +1,90 @@
+package yqlib
+
+import (
+	"testing"
+)
+
+var moduloOperatorScenarios = []expressionScenario{
+	{
+		skipDoc:    true,
+		document:   `{}`,
+		expression: "(.a % .b) as $x",
+		expected: []string{
+			"D0, P[], (doc)::{}\n",
+		},
+	},
+	{
+		description:    "Number modulo - int",
+		subdescription: "If the lhs and rhs are ints then the expression will be calculated with ints.",
+		document:       `{a: 13, b: 2}`,
+		expression:     `.a = .a % .b`,
+		expected: []string{
+			"D0, P[], (doc)::{a: 1, b: 2}\n",
+		},
+	},
+	{
+		description:    "Number modulo - float",
+		subdescription: "If either the lhs or rhs are floats then the expression will be calculated with floats.",
+		document:       `{a: 13, b: 2.5}`,
+		expression:     `.a = .a % .b`,
+		expected: []string{
+			"D0, P[], (doc)::{a: 0.5, b: 2.5}\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   `{a: 12, b: 2.5}`,
+		expression: `.a % .b | tag`,
+		expected: []string{
+			"D0, P[a], (!!str)::!!float\n",
+		},
+	},
+	{
+		description: "Update modulo",
+		document:    `{a: [7, 8, 9]}`,
+		expression:  `.a[] %= 3`,
+		expected: []string{
+			"D0, P[], (doc)::{a: [1, 2, 0]}\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `-7 % 3`,
+		expected: []string{
+			"D0, P[], (!!int)::-1\n",
+		},
+	},
+	{
+		description:    "Modulo by zero",
+		subdescription: "Like division, taking the modulo of zero is an error",
+		document:       `{a: 1, b: 0}`,
+		expression:     `.a % .b`,
+		expectedError:  "cannot calculate the modulo of 1 (a) by zero (b)",
+	},
+	{
+		skipDoc:       true,
+		document:      `{a: cat, b: 2}`,
+		expression:    `.a % .b`,
+		expectedError: "cannot calculate the modulo of !!str (a) by !!int (b)",
+	},
+	{
+		skipDoc:       true,
+		expression:    `1 % 0`,
+		expectedError: "cannot calculate the modulo of 1 by zero",
+	},
+	{
+		description: "Custom types: that are really numbers",
+		document:    "a: !horse 13\nb: !goat 2",
+		expression:  `.a = .a % .b`,
+		expected: []string{
+			"D0, P[], (doc)::a: !horse 1\nb: !goat 2\n",
+		},
+	},
+}
+
+func TestModuloOperatorScenarios(t *testing.T) {
+	for _, tt := range moduloOperatorScenarios {
+		testScenario(t, &tt)
+	}
+	documentOperatorScenarios(t, "modulo", moduloOperatorScenarios)
+}
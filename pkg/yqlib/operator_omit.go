@@ -0,0 +1,89 @@
+package yqlib
+
+import (
+	"container/list"
+	"fmt"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func omitMap(original *yaml.Node, keysToOmit *yaml.Node) *yaml.Node {
+
+	filteredContent := make([]*yaml.Node, 0)
+	for index := 0; index < len(original.Content); index = index + 2 {
+		key := original.Content[index]
+
+		if findInArray(keysToOmit, key) == -1 {
+			clonedKey := deepClone(key)
+			clonedValue := deepClone(original.Content[index+1])
+			filteredContent = append(filteredContent, clonedKey, clonedValue)
+		}
+	}
+
+	newNode := deepCloneNoContent(original)
+	newNode.Content = filteredContent
+
+	return newNode
+}
+
+func omitSequence(original *yaml.Node, indicesToOmit *yaml.Node) (*yaml.Node, error) {
+
+	omit := make(map[int]bool)
+	for index := 0; index < len(indicesToOmit.Content); index = index + 1 {
+		indexInArray, err := parseInt(indicesToOmit.Content[index].Value)
+		if err != nil {
+			return nil, fmt.Errorf("cannot index array with %v", indicesToOmit.Content[index].Value)
+		}
+		omit[indexInArray] = true
+	}
+
+	filteredContent := make([]*yaml.Node, 0)
+	for index := 0; index < len(original.Content); index = index + 1 {
+		if !omit[index] {
+			filteredContent = append(filteredContent, deepClone(original.Content[index]))
+		}
+	}
+
+	newNode := deepCloneNoContent(original)
+	newNode.Content = filteredContent
+
+	return newNode, nil
+}
+
+func omitOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("Omit")
+
+	contextIndicesToOmit, err := d.GetMatchingNodes(context, expressionNode.RHS)
+
+	if err != nil {
+		return Context{}, err
+	}
+	indicesToOmit := &yaml.Node{}
+	if contextIndicesToOmit.MatchingNodes.Len() > 0 {
+		indicesToOmit = contextIndicesToOmit.MatchingNodes.Front().Value.(*CandidateNode).Node
+	}
+
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		node := unwrapDoc(candidate.Node)
+
+		var replacement *yaml.Node
+		if node.Kind == yaml.MappingNode {
+			replacement = omitMap(node, indicesToOmit)
+		} else if node.Kind == yaml.SequenceNode {
+			replacement, err = omitSequence(node, indicesToOmit)
+			if err != nil {
+				return Context{}, err
+			}
+
+		} else {
+			return Context{}, fmt.Errorf("cannot omit indices from type %v (%v)", node.Tag, candidate.GetNicePath())
+		}
+
+		results.PushBack(candidate.CreateReplacementWithDocWrappers(replacement))
+	}
+
+	return context.ChildContext(results), nil
+}
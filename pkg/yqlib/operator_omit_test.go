@@ -0,0 +1,57 @@
+package yqlib
+
+import (
+	"testing"
+)
+
+var omitOperatorScenarios = []expressionScenario{
+	{
+		description:    "Omit keys from map",
+		subdescription: "Note that non existent keys are skipped.",
+		document:       "myMap: {cat: meow, dog: bark, thing: hamster, hamster: squeek}\n",
+		expression:     `.myMap |= omit(["hamster", "cat", "goat"])`,
+		expected: []string{
+			"D0, P[], (doc)::myMap: {dog: bark, thing: hamster}\n",
+		},
+	},
+	{
+		description: "Omit keys from map with comments",
+		skipDoc:     true,
+		document:    "# abc\nmyMap:\n  cat: meow # cute\n  dog: bark\n# xyz\n",
+		expression:  `.myMap |= omit(["dog"])`,
+		expected: []string{
+			"D0, P[], (doc)::# abc\nmyMap:\n    cat: meow # cute\n# xyz\n",
+		},
+	},
+	{
+		description:    "Omit a computed list of keys",
+		subdescription: "Unlike `del`, the keys can come from an expression.",
+		document:       "config: {name: web, secret: abc, token: xyz}\nsensitive: [secret, token]\n",
+		expression:     `.sensitive as $sensitive | .config |= omit($sensitive)`,
+		expected: []string{
+			"D0, P[], (doc)::config: {name: web}\nsensitive: [secret, token]\n",
+		},
+	},
+	{
+		description:    "Omit indices from array",
+		subdescription: "Note that non existent indices are skipped.",
+		document:       `[cat, leopard, lion]`,
+		expression:     `omit([2, 0, 734, -5])`,
+		expected: []string{
+			"D0, P[], (!!seq)::[leopard]\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		document:      `cat`,
+		expression:    `omit([0])`,
+		expectedError: "cannot omit indices from type !!str ()",
+	},
+}
+
+func TestOmitOperatorScenarios(t *testing.T) {
+	for _, tt := range omitOperatorScenarios {
+		testScenario(t, &tt)
+	}
+	documentOperatorScenarios(t, "omit", omitOperatorScenarios)
+}
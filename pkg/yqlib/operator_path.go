@@ -40,6 +40,19 @@ func getPathArrayFromNode(funcName string, node *yaml.Node) ([]interface{}, erro
 	return path, nil
 }
 
+// getPathArrayFromArgument is like getPathArrayFromNode, but the whole argument
+// can also be a json pointer (e.g. "/a/0") or dotted path (e.g. "a[0]") string.
+func getPathArrayFromArgument(funcName string, node *yaml.Node) ([]interface{}, error) {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+		path, err := parsePathString(node.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", funcName, err)
+		}
+		return path, nil
+	}
+	return getPathArrayFromNode(funcName, node)
+}
+
 // SETPATH(pathArray; value)
 func setPathOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
 	log.Debugf("SetPath")
@@ -59,7 +72,7 @@ func setPathOperator(d *dataTreeNavigator, context Context, expressionNode *Expr
 	}
 	lhsValue := lhsPathContext.MatchingNodes.Front().Value.(*CandidateNode)
 
-	lhsPath, err := getPathArrayFromNode("SETPATH", lhsValue.Node)
+	lhsPath, err := getPathArrayFromArgument("SETPATH", lhsValue.Node)
 
 	if err != nil {
 		return Context{}, err
@@ -101,7 +114,7 @@ func setPathOperator(d *dataTreeNavigator, context Context, expressionNode *Expr
 
 func delPathsOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
 	log.Debugf("delPaths")
-	// single RHS expression that returns an array of paths (array of arrays)
+	// single RHS expression that returns an array of paths (array of arrays), or a single path string
 
 	pathArraysContext, err := d.GetMatchingNodes(context.ReadOnlyClone(), expressionNode.RHS)
 	if err != nil {
@@ -112,12 +125,18 @@ func delPathsOperator(d *dataTreeNavigator, context Context, expressionNode *Exp
 	}
 	pathArraysNode := pathArraysContext.MatchingNodes.Front().Value.(*CandidateNode).Node
 
-	if pathArraysNode.Tag != "!!seq" {
+	var paths [][]interface{}
+	if pathArraysNode.Tag == "!!str" {
+		// a single json pointer or dotted path string
+		path, err := getPathArrayFromArgument("DELPATHS", pathArraysNode)
+		if err != nil {
+			return Context{}, err
+		}
+		paths = append(paths, path)
+	} else if pathArraysNode.Tag != "!!seq" {
 		return Context{}, fmt.Errorf("DELPATHS: expected a sequence of sequences, but found %v", pathArraysNode.Tag)
 	}
 
-	updatedContext := context
-
 	for i, child := range pathArraysNode.Content {
 
 		if child.Tag != "!!seq" {
@@ -128,6 +147,12 @@ func delPathsOperator(d *dataTreeNavigator, context Context, expressionNode *Exp
 		if err != nil {
 			return Context{}, err
 		}
+		paths = append(paths, childPath)
+	}
+
+	updatedContext := context
+
+	for _, childPath := range paths {
 
 		childTraversalExp := createTraversalTree(childPath, traversePreferences{}, false)
 		deleteChildOp := &Operation{OperationType: deleteChildOpType}
@@ -156,16 +181,127 @@ func getPathOperator(d *dataTreeNavigator, context Context, expressionNode *Expr
 
 	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
 		candidate := el.Value.(*CandidateNode)
-		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		result := candidate.CreateReplacement(createPathArrayNode(candidate.Path))
+		results.PushBack(result)
+	}
+
+	return context.ChildContext(results), nil
+}
 
-		content := make([]*yaml.Node, len(candidate.Path))
-		for pathIndex := 0; pathIndex < len(candidate.Path); pathIndex++ {
-			path := candidate.Path[pathIndex]
-			content[pathIndex] = createPathNodeFor(path)
+type pathsPreferences struct {
+	LeavesOnly bool
+}
+
+// PATHS and LEAF_PATHS - all the paths to nodes under the candidate, relative to it.
+func pathsOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("Paths")
+
+	var preferences pathsPreferences
+	if expressionNode.Operation.Preferences != nil {
+		preferences = expressionNode.Operation.Preferences.(pathsPreferences)
+	}
+	return paths(d, context, preferences, nil)
+}
+
+// PATHS(filter) - the paths to nodes under the candidate that match the filter.
+func pathsWithFilterOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("PathsWithFilter")
+	return paths(d, context, pathsPreferences{}, expressionNode.RHS)
+}
+
+func paths(d *dataTreeNavigator, context Context, preferences pathsPreferences, filter *ExpressionNode) (Context, error) {
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+
+		descendants := list.New()
+		recursePreferences := recursiveDescentPreferences{RecurseArray: true, TraversePreferences: traversePreferences{DontFollowAlias: true}}
+		err := recursiveDecent(descendants, context.SingleReadonlyChildContext(candidate), recursePreferences)
+		if err != nil {
+			return Context{}, err
+		}
+
+		// the first one is the candidate itself, which has an empty path
+		for descendantEl := descendants.Front().Next(); descendantEl != nil; descendantEl = descendantEl.Next() {
+			descendant := descendantEl.Value.(*CandidateNode)
+
+			if preferences.LeavesOnly && (descendant.Node.Kind == yaml.MappingNode || descendant.Node.Kind == yaml.SequenceNode) {
+				continue
+			}
+
+			if filter != nil {
+				include, err := matchesFilter(d, context.SingleReadonlyChildContext(descendant), filter)
+				if err != nil {
+					return Context{}, err
+				}
+				if !include {
+					continue
+				}
+			}
+
+			relativePath := descendant.Path[len(candidate.Path):]
+			results.PushBack(candidate.CreateReplacement(createPathArrayNode(relativePath)))
+		}
+	}
+
+	return context.ChildContext(results), nil
+}
+
+// like select, true if any of the results are truthy.
+func matchesFilter(d *dataTreeNavigator, context Context, filter *ExpressionNode) (bool, error) {
+	filterResults, err := d.GetMatchingNodes(context, filter)
+	if err != nil {
+		return false, err
+	}
+	for resultEl := filterResults.MatchingNodes.Front(); resultEl != nil; resultEl = resultEl.Next() {
+		truthy, err := isTruthy(resultEl.Value.(*CandidateNode))
+		if err != nil {
+			return false, err
+		}
+		if truthy {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GETPATH(pathArray) - the value at the given path, or null if there is nothing there.
+func getPathValueOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("GetPathValue")
+
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+
+		pathArrays, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(candidate), expressionNode.RHS)
+		if err != nil {
+			return Context{}, err
+		}
+
+		for pathEl := pathArrays.MatchingNodes.Front(); pathEl != nil; pathEl = pathEl.Next() {
+			path, err := getPathArrayFromArgument("GETPATH", unwrapDoc(pathEl.Value.(*CandidateNode).Node))
+			if err != nil {
+				return Context{}, err
+			}
+
+			traversalTree := createTraversalTree(path, traversePreferences{DontAutoCreate: true}, false)
+			found, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(candidate), traversalTree)
+			if err != nil {
+				return Context{}, err
+			}
+
+			if found.MatchingNodes.Len() == 0 {
+				missing := candidate.CreateReplacement(createScalarNode(nil, "null"))
+				// don't use append on the candidate's path, it may modify the path of the original node
+				missingPath := make([]interface{}, len(candidate.Path), len(candidate.Path)+len(path))
+				copy(missingPath, candidate.Path)
+				missing.Path = append(missingPath, path...)
+				results.PushBack(missing)
+			}
+			results.PushBackList(found.MatchingNodes)
 		}
-		node.Content = content
-		result := candidate.CreateReplacement(node)
-		results.PushBack(result)
 	}
 
 	return context.ChildContext(results), nil
@@ -0,0 +1,206 @@
+package yqlib
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+var jsonPointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+var arrayIndexRegex = regexp.MustCompile(`^(0|[1-9][0-9]*)$`)
+
+func createPathArrayNode(path []interface{}) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: make([]*yaml.Node, len(path))}
+	for pathIndex, pathElement := range path {
+		node.Content[pathIndex] = createPathNodeFor(pathElement)
+	}
+	return node
+}
+
+// parsePathString parses either a json pointer (e.g. /a/0/b) or a dotted path (e.g. a[0].b)
+func parsePathString(value string) ([]interface{}, error) {
+	if value == "" || strings.HasPrefix(value, "/") {
+		return parseJSONPointer(value)
+	}
+	return parseDottedPath(value)
+}
+
+// parseJSONPointer parses a rfc6901 json pointer, tokens that look like array indices are returned as ints.
+func parseJSONPointer(value string) ([]interface{}, error) {
+	if value == "" {
+		return []interface{}{}, nil
+	}
+	if !strings.HasPrefix(value, "/") {
+		return nil, fmt.Errorf("invalid json pointer '%v', it must be empty or start with '/'", value)
+	}
+	tokens := strings.Split(value[1:], "/")
+	path := make([]interface{}, len(tokens))
+	for i, token := range tokens {
+		if arrayIndexRegex.MatchString(token) {
+			index, err := strconv.Atoi(token)
+			if err == nil {
+				path[i] = index
+				continue
+			}
+		}
+		path[i] = jsonPointerUnescaper.Replace(token)
+	}
+	return path, nil
+}
+
+// parseDottedPath parses property style paths, where array indices can be
+// given either in brackets or as dotted numbers, e.g. a[0].b or a.0.b
+// Keys that contain dots or brackets are quoted in brackets, e.g. a["b.c"]
+func parseDottedPath(value string) ([]interface{}, error) {
+	path := make([]interface{}, 0)
+	runes := []rune(value)
+	var segment strings.Builder
+
+	flushSegment := func() {
+		if segment.Len() > 0 {
+			path = append(path, parsePathSegment(segment.String()))
+			segment.Reset()
+		}
+	}
+
+	for i := 0; i < len(runes); i++ {
+		switch runes[i] {
+		case '.':
+			flushSegment()
+		case '[':
+			flushSegment()
+			pathElement, end, err := parseDottedPathBrackets(runes, i)
+			if err != nil {
+				return nil, fmt.Errorf("invalid dotted path '%v', %w", value, err)
+			}
+			path = append(path, pathElement)
+			i = end
+		default:
+			segment.WriteRune(runes[i])
+		}
+	}
+	flushSegment()
+	return path, nil
+}
+
+func parsePathSegment(segment string) interface{} {
+	if arrayIndexRegex.MatchString(segment) {
+		if index, err := strconv.Atoi(segment); err == nil {
+			return index
+		}
+	}
+	return segment
+}
+
+// parseDottedPathBrackets parses the array index or quoted key in the brackets
+// starting at runes[start], returning it and the position of the closing bracket.
+func parseDottedPathBrackets(runes []rune, start int) (interface{}, int, error) {
+	i := start + 1
+	if i < len(runes) && runes[i] == '"' {
+		var key strings.Builder
+		for i = i + 1; i < len(runes) && runes[i] != '"'; i++ {
+			if runes[i] == '\\' && i+1 < len(runes) {
+				i++
+			}
+			key.WriteRune(runes[i])
+		}
+		if i+1 >= len(runes) || runes[i+1] != ']' {
+			return nil, 0, fmt.Errorf("quoted key is not terminated with '\"]'")
+		}
+		return key.String(), i + 1, nil
+	}
+
+	end := i
+	for end < len(runes) && runes[end] != ']' {
+		end++
+	}
+	if end >= len(runes) {
+		return nil, 0, fmt.Errorf("missing ']'")
+	}
+	index := string(runes[i:end])
+	if !arrayIndexRegex.MatchString(index) {
+		return nil, 0, fmt.Errorf("expected an array index or a quoted key in brackets, but got '%v'", index)
+	}
+	number, err := strconv.Atoi(index)
+	if err != nil {
+		return nil, 0, err
+	}
+	return number, end, nil
+}
+
+var dottedPathKeyEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// formatDottedPathKey quotes keys that would otherwise be parsed as something else
+func formatDottedPathKey(key string, first bool) string {
+	if key == "" || strings.ContainsAny(key, `.[]"`) || arrayIndexRegex.MatchString(key) {
+		return fmt.Sprintf(`["%v"]`, dottedPathKeyEscaper.Replace(key))
+	}
+	if first {
+		return key
+	}
+	return "." + key
+}
+
+func toJSONPointerOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- toJSONPointerOperator")
+	return conversionOperator(context, func(candidate *CandidateNode, node *yaml.Node) (*yaml.Node, error) {
+		path, err := getPathArrayFromNode("TO_JSON_POINTER", node)
+		if err != nil {
+			return nil, err
+		}
+		var pointer strings.Builder
+		for _, pathElement := range path {
+			pointer.WriteString("/")
+			pointer.WriteString(jsonPointerEscaper.Replace(fmt.Sprintf("%v", pathElement)))
+		}
+		return createStringScalarNode(pointer.String()), nil
+	})
+}
+
+func toDottedPathOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- toDottedPathOperator")
+	return conversionOperator(context, func(candidate *CandidateNode, node *yaml.Node) (*yaml.Node, error) {
+		path, err := getPathArrayFromNode("TO_DOTTED_PATH", node)
+		if err != nil {
+			return nil, err
+		}
+		var dottedPath strings.Builder
+		for i, pathElement := range path {
+			switch pathElement := pathElement.(type) {
+			case string:
+				dottedPath.WriteString(formatDottedPathKey(pathElement, i == 0))
+			default:
+				dottedPath.WriteString(fmt.Sprintf("[%v]", pathElement))
+			}
+		}
+		return createStringScalarNode(dottedPath.String()), nil
+	})
+}
+
+func fromPathString(context Context, funcName string, parse func(string) ([]interface{}, error)) (Context, error) {
+	return conversionOperator(context, func(candidate *CandidateNode, node *yaml.Node) (*yaml.Node, error) {
+		node = resolveAlias(node)
+		if node.Kind != yaml.ScalarNode || guessTagFromCustomType(node) != "!!str" {
+			return nil, fmt.Errorf("%v: expected a string, but got %v instead", funcName, node.Tag)
+		}
+		path, err := parse(node.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", funcName, err)
+		}
+		return createPathArrayNode(path), nil
+	})
+}
+
+func fromJSONPointerOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- fromJSONPointerOperator")
+	return fromPathString(context, "FROM_JSON_POINTER", parseJSONPointer)
+}
+
+func fromDottedPathOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- fromDottedPathOperator")
+	return fromPathString(context, "FROM_DOTTED_PATH", parseDottedPath)
+}
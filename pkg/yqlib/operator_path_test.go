@@ -136,6 +136,229 @@ var pathOperatorScenarios = []expressionScenario{
 		expression:     `delpaths(["a", 0])`,
 		expectedError:  "DELPATHS: expected entry [0] to be a sequence, but its a !!str. Note that delpaths takes an array of path arrays, e.g. [[\"a\", \"b\"]]",
 	},
+	{
+		description:    "List all paths",
+		subdescription: "Paths are relative to the matching node, and the node itself (the empty path) is not included.",
+		document:       `{a: {b: [cat, dog]}, c: frog}`,
+		expression:     `[paths]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- - a\n- - a\n  - b\n- - a\n  - b\n  - 0\n- - a\n  - b\n  - 1\n- - c\n",
+		},
+	},
+	{
+		description: "List paths relative to a node",
+		skipDoc:     true,
+		document:    `{a: {b: [cat, dog]}, c: frog}`,
+		expression:  `.a | [paths]`,
+		expected: []string{
+			"D0, P[a], (!!seq)::- - b\n- - b\n  - 0\n- - b\n  - 1\n",
+		},
+	},
+	{
+		description: "List paths of an empty map",
+		skipDoc:     true,
+		document:    `{}`,
+		expression:  `[paths]`,
+		expected: []string{
+			"D0, P[], (!!seq)::[]\n",
+		},
+	},
+	{
+		description:    "List paths matching a filter",
+		subdescription: "Only paths to nodes where the filter is true are returned.",
+		document:       `{a: {b: [cat, 3]}, c: 4}`,
+		expression:     `[paths(tag == "!!int")]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- - a\n  - b\n  - 1\n- - c\n",
+		},
+	},
+	{
+		description:    "List leaf paths",
+		subdescription: "Paths to all the scalars.",
+		document:       `{a: {b: [cat, dog]}, c: frog}`,
+		expression:     `[leaf_paths]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- - a\n  - b\n  - 0\n- - a\n  - b\n  - 1\n- - c\n",
+		},
+	},
+	{
+		description: "Get value by path",
+		document:    `{a: {b: [cat, dog]}}`,
+		expression:  `getpath(["a", "b", 1])`,
+		expected: []string{
+			"D0, P[a b 1], (!!str)::dog\n",
+		},
+	},
+	{
+		description:    "Get value by a path that does not exist",
+		subdescription: "Like jq, missing paths return null.",
+		document:       `{a: {b: [cat, dog]}}`,
+		expression:     `getpath(["a", "x", 1])`,
+		expected: []string{
+			"D0, P[a x 1], (!!null)::null\n",
+		},
+	},
+	{
+		description: "Get values by several paths that do not exist",
+		skipDoc:     true,
+		document:    `{a: {b: cat}}`,
+		expression:  `.a | getpath(["x"], ["y", "z"])`,
+		expected: []string{
+			"D0, P[a x], (!!null)::null\n",
+			"D0, P[a y z], (!!null)::null\n",
+		},
+	},
+	{
+		description:    "Get value using a path from the document",
+		subdescription: "The path array can come from any expression.",
+		document:       `{lookup: [a, b], a: {b: cat}}`,
+		expression:     `getpath(.lookup)`,
+		expected: []string{
+			"D0, P[a b], (!!str)::cat\n",
+		},
+	},
+	{
+		description: "Get value with a dotted path string",
+		skipDoc:     true,
+		document:    `{a: cat}`,
+		expression:  `getpath("a")`,
+		expected: []string{
+			"D0, P[a], (!!str)::cat\n",
+		},
+	},
+	{
+		description:   "Get value with a bad path",
+		skipDoc:       true,
+		document:      `{a: cat}`,
+		expression:    `getpath(1)`,
+		expectedError: "GETPATH: expected path array, but got !!int instead",
+	},
+	{
+		description:    "Convert a path to a JSON pointer",
+		subdescription: "`~` and `/` in keys are escaped as `~0` and `~1`, as per [rfc6901](https://rfc-editor.org/rfc/rfc6901.html).",
+		document:       `{a: {"b/c": [cat, dog]}}`,
+		expression:     `.a["b/c"][1] | path | to_json_pointer`,
+		expected: []string{
+			"D0, P[a b/c 1], (!!str)::/a/b~1c/1\n",
+		},
+	},
+	{
+		description: "Convert a JSON pointer to a path",
+		expression:  `"/a/b~1c/1" | from_json_pointer`,
+		expected: []string{
+			"D0, P[], (!!seq)::- a\n- b/c\n- 1\n",
+		},
+	},
+	{
+		description:   "Convert an invalid JSON pointer",
+		skipDoc:       true,
+		expression:    `"a/b" | from_json_pointer`,
+		expectedError: "FROM_JSON_POINTER: invalid json pointer 'a/b', it must be empty or start with '/'",
+	},
+	{
+		description: "Convert the empty JSON pointer",
+		skipDoc:     true,
+		expression:  `"" | from_json_pointer`,
+		expected: []string{
+			"D0, P[], (!!seq)::[]\n",
+		},
+	},
+	{
+		description: "Convert a path to a dotted path",
+		document:    `{a: {b: [cat, {c: dog}]}}`,
+		expression:  `.a.b[1].c | path | to_dotted_path`,
+		expected: []string{
+			"D0, P[a b 1 c], (!!str)::a.b[1].c\n",
+		},
+	},
+	{
+		description:    "Convert a dotted path to a path",
+		subdescription: "Array indices can be given in brackets or as numbers, like in properties files.",
+		expression:     `"a.b[1].c" | from_dotted_path`,
+		expected: []string{
+			"D0, P[], (!!seq)::- a\n- b\n- 1\n- c\n",
+		},
+	},
+	{
+		description: "Convert a properties style dotted path to a path",
+		skipDoc:     true,
+		expression:  `"a.b.1.c" | from_dotted_path`,
+		expected: []string{
+			"D0, P[], (!!seq)::- a\n- b\n- 1\n- c\n",
+		},
+	},
+	{
+		description:    "Convert a path with special keys to a dotted path",
+		subdescription: "Keys that contain dots, brackets or quotes, or that look like array indices, are quoted in brackets so that the dotted path can be converted back.",
+		document:       `{a: {"b.c": {"1": cat}}}`,
+		expression:     `.a["b.c"]["1"] | path | to_dotted_path`,
+		expected: []string{
+			"D0, P[a b.c 1], (!!str)::a[\"b.c\"][\"1\"]\n",
+		},
+	},
+	{
+		description: "Convert a dotted path with quoted keys to a path",
+		skipDoc:     true,
+		expression:  `"a[\"b.c\"][0]" | from_dotted_path`,
+		expected: []string{
+			"D0, P[], (!!seq)::- a\n- b.c\n- 0\n",
+		},
+	},
+	{
+		description: "Dotted paths round trip",
+		skipDoc:     true,
+		document:    `{"a.b": {"[c]": {"": {"d\"e": [{"2": x}]}}}}`,
+		expression:  `[paths] | .[-1] | to_dotted_path | from_dotted_path`,
+		expected: []string{
+			"D0, P[-1], (!!seq)::- a.b\n- '[c]'\n- \"\"\n- d\"e\n- 0\n- \"2\"\n",
+		},
+	},
+	{
+		description:   "Convert an invalid dotted path",
+		skipDoc:       true,
+		expression:    `"a[b]" | from_dotted_path`,
+		expectedError: "FROM_DOTTED_PATH: invalid dotted path 'a[b]', expected an array index or a quoted key in brackets, but got 'b'",
+	},
+	{
+		description:   "Convert a dotted path with an unterminated quoted key",
+		skipDoc:       true,
+		expression:    `"a[\"b" | from_dotted_path`,
+		expectedError: "FROM_DOTTED_PATH: invalid dotted path 'a[\"b', quoted key is not terminated with '\"]'",
+	},
+	{
+		description:    "Use path strings with getpath, setpath and delpaths",
+		subdescription: "A JSON pointer or dotted path string can be given in place of a path array. Strings starting with `/` (or empty) are JSON pointers, otherwise they are dotted paths.",
+		document:       `{a: {b: [cat, dog]}, c: frog}`,
+		expression:     `setpath("/a/b/0"; getpath("a.b[1]")) | delpaths("c")`,
+		expected: []string{
+			"D0, P[], (doc)::{a: {b: [dog, dog]}}\n",
+		},
+	},
+	{
+		description: "Get value with a JSON pointer string",
+		skipDoc:     true,
+		document:    `{a: {"b/c": [cat, dog]}}`,
+		expression:  `getpath("/a/b~1c/1")`,
+		expected: []string{
+			"D0, P[a b/c 1], (!!str)::dog\n",
+		},
+	},
+	{
+		description: "Delete paths given as a path string",
+		skipDoc:     true,
+		document:    `{a: {b: [cat, dog]}, c: frog}`,
+		expression:  `delpaths("/a/b/0")`,
+		expected: []string{
+			"D0, P[], (doc)::{a: {b: [dog]}, c: frog}\n",
+		},
+	},
+	{
+		description:   "Path strings must be the whole argument of delpaths",
+		skipDoc:       true,
+		document:      `{a: {b: cat}, c: frog}`,
+		expression:    `delpaths(["a", "c"])`,
+		expectedError: "DELPATHS: expected entry [0] to be a sequence, but its a !!str. Note that delpaths takes an array of path arrays, e.g. [[\"a\", \"b\"]]",
+	},
 }
 
 func TestPathOperatorsScenarios(t *testing.T) {
@@ -0,0 +1,79 @@
+package yqlib
+
+import (
+	"fmt"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// recurse(f) outputs the input, then recursively applies f to each of its results.
+// recurse(f; cond) only continues on with the results of f that match cond.
+func recurseOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- recurseOperator")
+	results, err := collectResults(func(emit emitFunction) (bool, error) {
+		return generateRecurse(d, context, expressionNode, emit)
+	})
+	if err != nil {
+		return Context{}, err
+	}
+	return context.ChildContext(results), nil
+}
+
+func generateRecurse(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode, emit emitFunction) (bool, error) {
+	args := getFunctionArguments(expressionNode.RHS)
+	if len(args) > 2 {
+		return false, fmt.Errorf("recurse must be given an expression and optionally a condition, e.g. recurse(.children[]; . != null)")
+	}
+	var condition *ExpressionNode
+	if len(args) == 2 {
+		condition = args[1]
+	}
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		stopped, err := recurse(d, context, candidate, args[0], condition, map[*yaml.Node]bool{}, emit)
+		if stopped || err != nil {
+			return stopped, err
+		}
+	}
+	return false, nil
+}
+
+// recurse keeps track of the nodes it is currently nested within, so that following
+// an alias back to one of its ancestors does not loop forever.
+func recurse(d *dataTreeNavigator, context Context, candidate *CandidateNode, next *ExpressionNode, condition *ExpressionNode, ancestors map[*yaml.Node]bool, emit emitFunction) (bool, error) {
+	stopped, err := emit(candidate)
+	if stopped || err != nil {
+		return stopped, err
+	}
+
+	node := resolveAlias(unwrapDoc(candidate.Node))
+	ancestors[node] = true
+	defer delete(ancestors, node)
+
+	children, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(candidate), next)
+	if err != nil {
+		return false, err
+	}
+
+	for el := children.MatchingNodes.Front(); el != nil; el = el.Next() {
+		child := el.Value.(*CandidateNode)
+		if ancestors[resolveAlias(unwrapDoc(child.Node))] {
+			log.Debugf("recurse: skipping %v, it has already been visited", child.GetNicePath())
+			continue
+		}
+		if condition != nil {
+			matches, err := matchesFilter(d, context.SingleReadonlyChildContext(child), condition)
+			if err != nil {
+				return false, err
+			} else if !matches {
+				continue
+			}
+		}
+		stopped, err := recurse(d, context, child, next, condition, ancestors, emit)
+		if stopped || err != nil {
+			return stopped, err
+		}
+	}
+	return false, nil
+}
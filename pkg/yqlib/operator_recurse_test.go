@@ -0,0 +1,94 @@
+package yqlib
+
+import (
+	"testing"
+)
+
+var recurseOperatorScenarios = []expressionScenario{
+	{
+		description:    "Recurse",
+		subdescription: "Without any arguments, recurse is the same as `..`",
+		document:       "a: [cat]\n",
+		expression:     `recurse`,
+		expected: []string{
+			"D0, P[], (!!map)::a: [cat]\n",
+			"D0, P[a], (!!seq)::[cat]\n",
+			"D0, P[a 0], (!!str)::cat\n",
+		},
+	},
+	{
+		description:    "Recurse through a tree",
+		subdescription: "Follows your own edges, rather than every child node.",
+		document:       "name: a\nchildren:\n  - name: b\n    children:\n      - name: c\n  - name: d\n",
+		expression:     `[recurse(.children[]?) | .name]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- a\n- b\n- c\n- d\n",
+		},
+	},
+	{
+		description:    "Recurse with a condition",
+		subdescription: "Only results of the expression that match the condition are recursed into (and returned).",
+		expression:     `[2 | recurse(. * .; . < 1000)]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 2\n- 4\n- 16\n- 256\n",
+		},
+	},
+	{
+		description: "Recurse until there are no results",
+		expression:  `[2 | recurse(select(. < 100) | . * 2)]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 2\n- 4\n- 8\n- 16\n- 32\n- 64\n- 128\n",
+		},
+	},
+	{
+		description:    "Stop recursing with empty",
+		subdescription: "Like jq, `empty` returns no results at all. Note that yq needs spaces around `*` here, `.*2` is read as a path.",
+		expression:     `[2 | recurse(if . < 100 then . * 2 else empty end)]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 2\n- 4\n- 8\n- 16\n- 32\n- 64\n- 128\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `[1, empty, 2]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 1\n- 2\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   "empty: 3\n",
+		expression: `.empty`,
+		expected: []string{
+			"D0, P[empty], (!!int)::3\n",
+		},
+	},
+	{
+		description:    "Aliases back to a parent are not followed",
+		subdescription: "So that cyclic documents do not recurse forever.",
+		document:       "root: &r\n  name: a\n  children:\n    - name: b\n      children: [*r]\n",
+		expression:     `[.root | recurse(.children[]?) | .name]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- a\n- b\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `[limit(3; 1 | recurse(. + 1))]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 1\n- 2\n- 3\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		expression:    `recurse(.a; .b; .c)`,
+		expectedError: "recurse must be given an expression and optionally a condition, e.g. recurse(.children[]; . != null)",
+	},
+}
+
+func TestRecurseOperatorScenarios(t *testing.T) {
+	for _, tt := range recurseOperatorScenarios {
+		testScenario(t, &tt)
+	}
+	documentOperatorScenarios(t, "recurse", recurseOperatorScenarios)
+}
@@ -55,3 +55,91 @@ func reduceOperator(d *dataTreeNavigator, context Context, expressionNode *Expre
 
 	return accum, nil
 }
+
+// reduceEachOperator is the jq style 'reduce <exp> as $x (<init>; <update>)', which reduces
+// each input separately - whereas ireduce reduces over all of them (e.g. all documents).
+func reduceEachOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- reduceEachOp")
+	var results = list.New()
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		reduced, err := reduceOperator(d, context.SingleChildContext(el.Value.(*CandidateNode)), expressionNode)
+		if err != nil {
+			return Context{}, err
+		}
+		results.PushBackList(reduced.MatchingNodes)
+	}
+	return context.ChildContext(results), nil
+}
+
+func foreachOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- foreachOp")
+	//foreach .a as $var (0; . + $var; [$var, .])
+	//lhs is the assignment operator
+	//rhs is the block of init, update and the optional extract
+	// '.' refers to the current accumulator, initialised to 0
+	// each intermediate state of the accumulator is passed through extract and returned
+
+	if expressionNode.LHS.Operation.OperationType != assignVariableOpType {
+		return Context{}, fmt.Errorf("foreach must be given a variables assignment, got %v instead", expressionNode.LHS.Operation.OperationType.Type)
+	} else if expressionNode.RHS.Operation.OperationType != blockOpType {
+		return Context{}, fmt.Errorf("foreach must be given a block, got %v instead", expressionNode.RHS.Operation.OperationType.Type)
+	}
+
+	variableName := expressionNode.LHS.RHS.Operation.StringValue
+
+	initExp := expressionNode.RHS.LHS
+	updateExp := expressionNode.RHS.RHS
+	var extractExp *ExpressionNode
+	if updateExp.Operation.OperationType == blockOpType {
+		extractExp = updateExp.RHS
+		updateExp = updateExp.LHS
+	}
+
+	var results = list.New()
+	// like jq, each input is iterated over separately
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		inputContext := context.SingleChildContext(el.Value.(*CandidateNode))
+		err := foreachInput(d, inputContext, expressionNode.LHS.LHS, variableName, initExp, updateExp, extractExp, results)
+		if err != nil {
+			return Context{}, err
+		}
+	}
+
+	return context.ChildContext(results), nil
+}
+
+func foreachInput(d *dataTreeNavigator, context Context, arrayExp *ExpressionNode, variableName string, initExp *ExpressionNode, updateExp *ExpressionNode, extractExp *ExpressionNode, results *list.List) error {
+	array, err := d.GetMatchingNodes(context, arrayExp)
+	if err != nil {
+		return err
+	}
+
+	accum, err := d.GetMatchingNodes(context, initExp)
+	if err != nil {
+		return err
+	}
+
+	for el := array.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		log.Debugf("FOREACH WITH %v", NodeToString(candidate))
+		l := list.New()
+		l.PushBack(candidate)
+		accum.SetVariable(variableName, l)
+
+		accum, err = d.GetMatchingNodes(accum, updateExp)
+		if err != nil {
+			return err
+		}
+
+		extracted := accum
+		if extractExp != nil {
+			extracted, err = d.GetMatchingNodes(accum, extractExp)
+			if err != nil {
+				return err
+			}
+		}
+		// the accumulator may be updated in place by the next iteration
+		results.PushBackList(extracted.DeepClone().MatchingNodes)
+	}
+	return nil
+}
@@ -30,6 +30,87 @@ var reduceOperatorScenarios = []expressionScenario{
 			"D0, P[], (!!map)::Cathy: apples\nBob: bananas\n",
 		},
 	},
+	{
+		description:    "jq style reduce",
+		subdescription: "The `jq` prefix syntax for reduce is also supported",
+		document:       `[10,2, 5, 3]`,
+		expression:     `reduce .[] as $item (0; . + $item)`,
+		expected: []string{
+			"D0, P[], (!!int)::20\n",
+		},
+	},
+	{
+		description: "jq style reduce within an expression",
+		skipDoc:     true,
+		document:    `[10,2, 5, 3]`,
+		expression:  `1 + reduce .[] as $item (0; . + $item) | . * 2`,
+		expected: []string{
+			"D0, P[], (!!int)::42\n",
+		},
+	},
+	{
+		description: "Nested jq style reduce",
+		skipDoc:     true,
+		document:    `{a: [1, 2], b: [10, 20]}`,
+		expression:  `.b as $b | reduce .a[] as $x (0; reduce $b[] as $y (.; . + $x * $y))`,
+		expected: []string{
+			"D0, P[], (!!int)::90\n",
+		},
+	},
+	{
+		description:    "Foreach",
+		subdescription: "Like reduce, but returns each intermediate value of the accumulator",
+		document:       `[10,2, 5, 3]`,
+		expression:     `[foreach .[] as $item (0; . + $item)]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 10\n- 12\n- 17\n- 20\n",
+		},
+	},
+	{
+		description:    "Foreach with extract",
+		subdescription: "The optional third expression is used to extract the output from the accumulator each iteration",
+		document:       `[cat, dog]`,
+		expression:     `[foreach .[] as $item ({"count": 0}; .count += 1; {"name": $item, "index": .count})]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- name: cat\n  index: 1\n- name: dog\n  index: 2\n",
+		},
+	},
+	{
+		description: "Foreach returns a copy of each intermediate state",
+		skipDoc:     true,
+		document:    `[a, b]`,
+		expression:  `[foreach .[] as $k ({"count": 0}; .count += 1)]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- count: 1\n- count: 2\n",
+		},
+	},
+	{
+		description:    "jq style reduce over several inputs",
+		subdescription: "Like jq, each input is reduced separately - unlike `ireduce`, which reduces over all of them",
+		document:       `[[1, 2], [3, 4]]`,
+		expression:     `[.[] | reduce .[] as $x (0; . + $x)]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 3\n- 7\n",
+		},
+	},
+	{
+		description: "Foreach over several inputs",
+		skipDoc:     true,
+		document:    `[[1, 2], [3, 4]]`,
+		expression:  `[.[] | foreach .[] as $x (0; . + $x)]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 1\n- 3\n- 3\n- 7\n",
+		},
+	},
+	{
+		description: "ireduce still reduces over all inputs",
+		skipDoc:     true,
+		document:    `[[1, 2], [3, 4]]`,
+		expression:  `[.[] | .[] as $x ireduce (0; . + $x)]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 10\n",
+		},
+	},
 }
 
 func TestReduceOperatorScenarios(t *testing.T) {
@@ -0,0 +1,153 @@
+package yqlib
+
+import (
+	"container/list"
+	"fmt"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+type setOperation func(keyedArrays [][]keyedNode) []*yaml.Node
+
+type keyedNode struct {
+	key  *yaml.Node
+	node *yaml.Node
+}
+
+func intersectionOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	selfExpression := &ExpressionNode{Operation: &Operation{OperationType: selfReferenceOpType}}
+	return setOperator(d, context, "intersection", selfExpression, intersectKeyedArrays)
+}
+
+func intersectionByOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	return setOperator(d, context, "intersection_by", expressionNode.RHS, intersectKeyedArrays)
+}
+
+func differenceByOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	return setOperator(d, context, "difference_by", expressionNode.RHS, differenceKeyedArrays)
+}
+
+func unionByOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	return setOperator(d, context, "union_by", expressionNode.RHS, unionKeyedArrays)
+}
+
+// setOperator expects an array of arrays (e.g. [.a, .b]), and compares their elements by the
+// (deep) equality of the key expression.
+func setOperator(d *dataTreeNavigator, context Context, operation string, keyExpression *ExpressionNode, combine setOperation) (Context, error) {
+	log.Debugf("-- setOperator %v", operation)
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		candidateNode := unwrapDoc(candidate.Node)
+
+		if candidateNode.Kind != yaml.SequenceNode {
+			return Context{}, fmt.Errorf("%v expects an array of arrays, e.g. [.a, .b] | %v, but got %v", operation, operation, candidateNode.Tag)
+		}
+
+		keyedArrays := make([][]keyedNode, len(candidateNode.Content))
+		for i, array := range candidateNode.Content {
+			array = resolveAlias(array)
+			if array.Kind != yaml.SequenceNode {
+				return Context{}, fmt.Errorf("%v expects an array of arrays, e.g. [.a, .b] | %v, but got an array containing %v", operation, operation, array.Tag)
+			}
+			keyed, err := keyArray(d, context, array, keyExpression)
+			if err != nil {
+				return Context{}, err
+			}
+			keyedArrays[i] = keyed
+		}
+
+		resultNode := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		resultNode.Content = combine(keyedArrays)
+		results.PushBack(candidate.CreateReplacement(resultNode))
+	}
+
+	return context.ChildContext(results), nil
+}
+
+func keyArray(d *dataTreeNavigator, context Context, array *yaml.Node, keyExpression *ExpressionNode) ([]keyedNode, error) {
+	keyed := make([]keyedNode, len(array.Content))
+	for i, node := range array.Content {
+		child := &CandidateNode{Node: node}
+		rhs, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(child), keyExpression)
+		if err != nil {
+			return nil, err
+		}
+
+		key := createScalarNode(nil, "null")
+		if rhs.MatchingNodes.Len() > 0 {
+			key = resolveAlias(unwrapDoc(rhs.MatchingNodes.Front().Value.(*CandidateNode).Node))
+		}
+		keyed[i] = keyedNode{key: key, node: node}
+	}
+	return keyed, nil
+}
+
+func containsKey(keyed []keyedNode, key *yaml.Node) bool {
+	for _, entry := range keyed {
+		if recursiveNodeEqual(entry.key, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// elements of the first array, whose key is found in all the other arrays
+func intersectKeyedArrays(keyedArrays [][]keyedNode) []*yaml.Node {
+	if len(keyedArrays) == 0 {
+		return []*yaml.Node{}
+	}
+	results := make([]*yaml.Node, 0)
+	for _, entry := range keyedArrays[0] {
+		inAll := true
+		for _, other := range keyedArrays[1:] {
+			if !containsKey(other, entry.key) {
+				inAll = false
+				break
+			}
+		}
+		if inAll {
+			results = append(results, entry.node)
+		}
+	}
+	return results
+}
+
+// elements of the first array, whose key is not found in any of the other arrays
+func differenceKeyedArrays(keyedArrays [][]keyedNode) []*yaml.Node {
+	if len(keyedArrays) == 0 {
+		return []*yaml.Node{}
+	}
+	results := make([]*yaml.Node, 0)
+	for _, entry := range keyedArrays[0] {
+		inAny := false
+		for _, other := range keyedArrays[1:] {
+			if containsKey(other, entry.key) {
+				inAny = true
+				break
+			}
+		}
+		if !inAny {
+			results = append(results, entry.node)
+		}
+	}
+	return results
+}
+
+// elements from all the arrays, unique by key - the first one found wins
+func unionKeyedArrays(keyedArrays [][]keyedNode) []*yaml.Node {
+	seen := make([]keyedNode, 0)
+	for _, keyed := range keyedArrays {
+		for _, entry := range keyed {
+			if !containsKey(seen, entry.key) {
+				seen = append(seen, entry)
+			}
+		}
+	}
+	results := make([]*yaml.Node, len(seen))
+	for i, entry := range seen {
+		results[i] = entry.node
+	}
+	return results
+}
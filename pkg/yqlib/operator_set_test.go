@@ -0,0 +1,94 @@
+package yqlib
+
+import (
+	"testing"
+)
+
+var setOperatorScenarios = []expressionScenario{
+	{
+		description:    "Intersection",
+		subdescription: "Elements of the first array that are (deeply) equal to an element in all the others.",
+		document:       "a: [cat, {b: dog}, frog]\nb: [{b: dog}, frog, goat]\n",
+		expression:     `[.a, .b] | intersection`,
+		expected: []string{
+			"D0, P[], (!!seq)::- {b: dog}\n- frog\n",
+		},
+	},
+	{
+		description: "Intersection by key",
+		document:    "a:\n  - {name: web, ip: 10.0.0.1}\n  - {name: db, ip: 10.0.0.2}\nb:\n  - {name: db}\n  - {name: cache}\n",
+		expression:  `[.a, .b] | intersection_by(.name)`,
+		expected: []string{
+			"D0, P[], (!!seq)::- {name: db, ip: 10.0.0.2}\n",
+		},
+	},
+	{
+		description:    "Difference by key",
+		subdescription: "Elements of the first array whose key is not found in any of the others.",
+		document:       "a:\n  - {name: web, ip: 10.0.0.1}\n  - {name: db, ip: 10.0.0.2}\nb:\n  - {name: db}\n  - {name: cache}\n",
+		expression:     `[.a, .b] | difference_by(.name)`,
+		expected: []string{
+			"D0, P[], (!!seq)::- {name: web, ip: 10.0.0.1}\n",
+		},
+	},
+	{
+		description:    "Union by key",
+		subdescription: "Elements from all the arrays, unique by key. When the key is found more than once, the first element wins.",
+		document:       "a:\n  - {name: web, image: nginx}\n  - {name: db, image: postgres}\nb:\n  - {name: db, image: mysql}\n  - {name: cache, image: redis}\n",
+		expression:     `[.a, .b] | union_by(.name)`,
+		expected: []string{
+			"D0, P[], (!!seq)::- {name: web, image: nginx}\n- {name: db, image: postgres}\n- {name: cache, image: redis}\n",
+		},
+	},
+	{
+		description: "Keys are compared deeply",
+		document:    "a: [{id: {x: 1, y: 2}, v: a}, {id: {x: 2}, v: b}]\nb: [{id: {x: 1, y: 2}}]\n",
+		expression:  `[.a, .b] | difference_by(.id) | .[].v`,
+		expected: []string{
+			"D0, P[0 v], (!!str)::b\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   "a: [1, 2, 3]\nb: [2, 3]\nc: [3]\n",
+		expression: `[.a, .b, .c] | intersection`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 3\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   "a: [1, 2]\n",
+		expression: `[.a] | difference_by(.)`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 1\n- 2\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   "a: &a [1, 2]\nb: [2]\n",
+		expression: `[.a, .b] | union_by(.)`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 1\n- 2\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		document:      "a: cat",
+		expression:    `intersection`,
+		expectedError: "intersection expects an array of arrays, e.g. [.a, .b] | intersection, but got !!map",
+	},
+	{
+		skipDoc:       true,
+		document:      "[1, [2]]",
+		expression:    `union_by(.)`,
+		expectedError: "union_by expects an array of arrays, e.g. [.a, .b] | union_by, but got an array containing !!int",
+	},
+}
+
+func TestSetOperatorScenarios(t *testing.T) {
+	for _, tt := range setOperatorScenarios {
+		testScenario(t, &tt)
+	}
+	documentOperatorScenarios(t, "set-operations", setOperatorScenarios)
+}
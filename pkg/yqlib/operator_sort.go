@@ -32,29 +32,9 @@ func sortByOperator(d *dataTreeNavigator, context Context, expressionNode *Expre
 			return context, fmt.Errorf("node at path [%v] is not an array (it's a %v)", candidate.GetNicePath(), candidate.GetNiceTag())
 		}
 
-		sortableArray := make(sortableNodeArray, len(candidateNode.Content))
-
-		for i, originalNode := range candidateNode.Content {
-
-			childCandidate := candidate.CreateChildInArray(i, originalNode)
-			compareContext, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(childCandidate), expressionNode.RHS)
-			if err != nil {
-				return Context{}, err
-			}
-
-			nodeToCompare := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}
-			if compareContext.MatchingNodes.Len() > 0 {
-				nodeToCompare = compareContext.MatchingNodes.Front().Value.(*CandidateNode).Node
-			}
-
-			log.Debug("going to compare %v by %v", NodeToString(candidate.CreateReplacement(originalNode)), NodeToString(candidate.CreateReplacement(nodeToCompare)))
-
-			sortableArray[i] = sortableNode{Node: originalNode, NodeToCompare: nodeToCompare, dateTimeLayout: context.GetDateTimeLayout()}
-
-			if nodeToCompare.Kind != yaml.ScalarNode {
-				return Context{}, fmt.Errorf("sort only works for scalars, got %v", nodeToCompare.Tag)
-			}
-
+		sortableArray, err := createSortableNodeArray(d, context, candidate, candidateNode, expressionNode.RHS, "sort")
+		if err != nil {
+			return Context{}, err
 		}
 
 		sort.Stable(sortableArray)
@@ -70,6 +50,34 @@ func sortByOperator(d *dataTreeNavigator, context Context, expressionNode *Expre
 	return context.ChildContext(results), nil
 }
 
+func createSortableNodeArray(d *dataTreeNavigator, context Context, candidate *CandidateNode, candidateNode *yaml.Node, compareExp *ExpressionNode, operation string) (sortableNodeArray, error) {
+	sortableArray := make(sortableNodeArray, len(candidateNode.Content))
+
+	for i, originalNode := range candidateNode.Content {
+
+		childCandidate := candidate.CreateChildInArray(i, originalNode)
+		compareContext, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(childCandidate), compareExp)
+		if err != nil {
+			return nil, err
+		}
+
+		nodeToCompare := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}
+		if compareContext.MatchingNodes.Len() > 0 {
+			nodeToCompare = compareContext.MatchingNodes.Front().Value.(*CandidateNode).Node
+		}
+
+		log.Debug("going to compare %v by %v", NodeToString(candidate.CreateReplacement(originalNode)), NodeToString(candidate.CreateReplacement(nodeToCompare)))
+
+		sortableArray[i] = sortableNode{Node: originalNode, NodeToCompare: nodeToCompare, dateTimeLayout: context.GetDateTimeLayout()}
+
+		if nodeToCompare.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("%v only works for scalars, got %v", operation, nodeToCompare.Tag)
+		}
+
+	}
+	return sortableArray, nil
+}
+
 type sortableNode struct {
 	Node           *yaml.Node
 	NodeToCompare  *yaml.Node
@@ -0,0 +1,179 @@
+package yqlib
+
+import (
+	"container/list"
+	"fmt"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// createStreamEvent creates a [path, leaf] event, or a [path] closing event when leaf is nil.
+func createStreamEvent(path []interface{}, leaf *yaml.Node) *yaml.Node {
+	event := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Style: yaml.FlowStyle, Content: []*yaml.Node{createPathArrayNode(path)}}
+	event.Content[0].Style = yaml.FlowStyle
+	if leaf != nil {
+		event.Content = append(event.Content, leaf)
+	}
+	return event
+}
+
+// streamEvents emits the events for the node, like jq: a [path, leaf] event for each scalar
+// (and empty map/array), and a [path] event after the last child of each map/array.
+func streamEvents(path []interface{}, node *yaml.Node, emit func(*yaml.Node)) {
+	node = resolveAlias(unwrapDoc(node))
+	if (node.Kind != yaml.MappingNode && node.Kind != yaml.SequenceNode) || len(node.Content) == 0 {
+		emit(createStreamEvent(path, node))
+		return
+	}
+
+	var lastKey interface{}
+	if node.Kind == yaml.MappingNode {
+		for index := 0; index < len(node.Content); index = index + 2 {
+			lastKey = node.Content[index].Value
+			streamEvents(appendPath(path, lastKey), node.Content[index+1], emit)
+		}
+	} else {
+		for index, child := range node.Content {
+			lastKey = index
+			streamEvents(appendPath(path, lastKey), child, emit)
+		}
+	}
+	emit(createStreamEvent(appendPath(path, lastKey), nil))
+}
+
+// don't use append as they may actually modify the original path!
+func appendPath(path []interface{}, key interface{}) []interface{} {
+	newPath := make([]interface{}, len(path)+1)
+	copy(newPath, path)
+	newPath[len(path)] = key
+	return newPath
+}
+
+func toStreamOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- toStreamOperator")
+
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		streamEvents([]interface{}{}, candidate.Node, func(event *yaml.Node) {
+			results.PushBack(candidate.CreateReplacement(event))
+		})
+	}
+
+	return context.ChildContext(results), nil
+}
+
+func parseStreamEvent(funcName string, event *yaml.Node) ([]interface{}, *yaml.Node, error) {
+	event = unwrapDoc(event)
+	if event.Kind != yaml.SequenceNode || len(event.Content) < 1 || len(event.Content) > 2 || event.Content[0].Kind != yaml.SequenceNode {
+		return nil, nil, fmt.Errorf("%v: expected a stream event like [path, leaf] or [path], but got a %v", funcName, event.Tag)
+	}
+	path, err := getPathArrayFromNode(funcName, event.Content[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(event.Content) == 1 {
+		return path, nil, nil
+	}
+	return path, event.Content[1], nil
+}
+
+// setStreamValue sets the leaf at the path, creating maps and arrays as needed.
+func setStreamValue(node *yaml.Node, path []interface{}, leaf *yaml.Node) *yaml.Node {
+	if len(path) == 0 {
+		return deepClone(leaf)
+	}
+
+	switch key := path[0].(type) {
+	case string:
+		if node == nil || node.Kind != yaml.MappingNode {
+			node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		}
+		for index := 0; index < len(node.Content); index = index + 2 {
+			if node.Content[index].Value == key {
+				node.Content[index+1] = setStreamValue(node.Content[index+1], path[1:], leaf)
+				return node
+			}
+		}
+		node.Content = append(node.Content, createStringScalarNode(key), setStreamValue(nil, path[1:], leaf))
+	default:
+		index := key.(int)
+		if node == nil || node.Kind != yaml.SequenceNode {
+			node = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		}
+		for len(node.Content) <= index {
+			node.Content = append(node.Content, createScalarNode(nil, "null"))
+		}
+		node.Content[index] = setStreamValue(node.Content[index], path[1:], leaf)
+	}
+	return node
+}
+
+func fromStreamOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- fromStreamOperator")
+
+	events, err := d.GetMatchingNodes(context.ReadOnlyClone(), expressionNode.RHS)
+	if err != nil {
+		return Context{}, err
+	}
+
+	var results = list.New()
+	var current *yaml.Node
+
+	for el := events.MatchingNodes.Front(); el != nil; el = el.Next() {
+		event := el.Value.(*CandidateNode)
+		path, leaf, err := parseStreamEvent("FROMSTREAM", event.Node)
+		if err != nil {
+			return Context{}, err
+		}
+
+		if leaf != nil {
+			current = setStreamValue(current, path, leaf)
+		}
+
+		// a top level scalar, or the closing event of a top level map/array
+		if (leaf != nil && len(path) == 0) || (leaf == nil && len(path) == 1) {
+			if current == nil {
+				return Context{}, fmt.Errorf("FROMSTREAM: got a closing event for %v before any values", path)
+			}
+			results.PushBack(&CandidateNode{Node: current, Document: event.Document, Filename: event.Filename, FileIndex: event.FileIndex})
+			current = nil
+		}
+	}
+
+	return context.ChildContext(results), nil
+}
+
+func truncateStreamOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- truncateStreamOperator")
+
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		depthNode := unwrapDoc(candidate.Node)
+		depth, err := parseInt(depthNode.Value)
+		if err != nil || depthNode.Kind != yaml.ScalarNode {
+			return Context{}, fmt.Errorf("TRUNCATE_STREAM: expected the depth to truncate by as input, but got %v", depthNode.Tag)
+		}
+
+		events, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(candidate), expressionNode.RHS)
+		if err != nil {
+			return Context{}, err
+		}
+
+		for eventEl := events.MatchingNodes.Front(); eventEl != nil; eventEl = eventEl.Next() {
+			event := eventEl.Value.(*CandidateNode)
+			path, leaf, err := parseStreamEvent("TRUNCATE_STREAM", event.Node)
+			if err != nil {
+				return Context{}, err
+			}
+			if len(path) > depth {
+				results.PushBack(event.CreateReplacement(createStreamEvent(path[depth:], leaf)))
+			}
+		}
+	}
+
+	return context.ChildContext(results), nil
+}
@@ -0,0 +1,90 @@
+package yqlib
+
+import (
+	"testing"
+)
+
+var streamOperatorScenarios = []expressionScenario{
+	{
+		description: "To stream",
+		document:    `{a: [cat, {b: dog}], c: {}}`,
+		expression:  `[tostream]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- [[a, 0], cat]\n- [[a, 1, b], dog]\n- [[a, 1, b]]\n- [[a, 1]]\n- [[c], {}]\n- [[c]]\n",
+		},
+	},
+	{
+		description: "To stream a scalar",
+		skipDoc:     true,
+		document:    `a: cat`,
+		expression:  `[.a | tostream]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- [[], cat]\n",
+		},
+	},
+	{
+		description: "To stream follows aliases",
+		skipDoc:     true,
+		document:    `{a: &x [1], b: *x}`,
+		expression:  `[.b | tostream]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- [[0], 1]\n- [[0]]\n",
+		},
+	},
+	{
+		description: "From stream",
+		expression:  `fromstream([["a", 0], "cat"], [["a", 1, "b"], "dog"], [["a", 1, "b"]], [["a", 1]], [["a"]])`,
+		expected: []string{
+			"D0, P[], (!!map)::a:\n    - cat\n    - b: dog\n",
+		},
+	},
+	{
+		description:    "Round trip",
+		subdescription: "Filter out events before reassembling them.",
+		document:       `{a: cat, b: [1, 2], c: dog}`,
+		expression:     `fromstream(tostream | select(.[0][0] != "b"))`,
+		expected: []string{
+			"D0, P[], (!!map)::a: cat\nc: dog\n",
+		},
+	},
+	{
+		description: "From stream with multiple values",
+		skipDoc:     true,
+		expression:  `fromstream([[], 1], [[0], 2], [[0]])`,
+		expected: []string{
+			"D0, P[], (!!int)::1\n",
+			"D0, P[], (!!seq)::- 2\n",
+		},
+	},
+	{
+		description:   "From stream with a bad event",
+		skipDoc:       true,
+		expression:    `fromstream("cat")`,
+		expectedError: "FROMSTREAM: expected a stream event like [path, leaf] or [path], but got a !!str",
+	},
+	{
+		description:    "Truncate stream",
+		subdescription: "Removes the first element of each event path, dropping events that would have an empty path. Note that the depth is the input.",
+		document:       `{a: [cat, dog]}`,
+		expression:     `[1 | truncate_stream([["a", 0], "cat"], [["a", 1], "dog"], [["a", 1]])]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- [[0], cat]\n- [[1], dog]\n- [[1]]\n",
+		},
+	},
+	{
+		description:    "Remove the top level of a document",
+		subdescription: "Combine `fromstream` and `truncate_stream` to reassemble the value under the first key.",
+		document:       `{a: [cat, {b: dog}]}`,
+		expression:     `. as $doc | fromstream(1 | truncate_stream($doc | tostream))`,
+		expected: []string{
+			"D0, P[], (!!seq)::- cat\n- b: dog\n",
+		},
+	},
+}
+
+func TestStreamOperatorScenarios(t *testing.T) {
+	for _, tt := range streamOperatorScenarios {
+		testScenario(t, &tt)
+	}
+	documentOperatorScenarios(t, "stream", streamOperatorScenarios)
+}
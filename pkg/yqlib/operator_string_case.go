@@ -0,0 +1,203 @@
+package yqlib
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+	"unicode"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+type stringCasePreferences struct {
+	Case string
+}
+
+// splitWords splits a string into words on anything that isn't a letter or
+// digit, as well as on changes of case, e.g. "HTTPServer_port" is split into
+// "HTTP", "Server" and "port".
+func splitWords(value string) []string {
+	words := make([]string, 0)
+	runes := []rune(value)
+	start := -1
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+		previous := runes[i-1]
+		startsWord := unicode.IsUpper(r) &&
+			(unicode.IsLower(previous) || unicode.IsDigit(previous) ||
+				// the last capital of an acronym starts the next word, e.g. the S in HTTPServer
+				(unicode.IsUpper(previous) && i+1 < len(runes) && unicode.IsLower(runes[i+1])))
+		if startsWord {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+func capitalise(word string) string {
+	runes := []rune(strings.ToLower(word))
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+func changeStringCase(value string, stringCase string) string {
+	words := splitWords(value)
+	for i, word := range words {
+		switch {
+		case stringCase == "camel" && i == 0, stringCase == "snake", stringCase == "kebab":
+			words[i] = strings.ToLower(word)
+		default:
+			words[i] = capitalise(word)
+		}
+	}
+
+	switch stringCase {
+	case "snake":
+		return strings.Join(words, "_")
+	case "kebab":
+		return strings.Join(words, "-")
+	case "title":
+		return strings.Join(words, " ")
+	}
+	return strings.Join(words, "")
+}
+
+func changeStringCaseOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	prefs := expressionNode.Operation.Preferences.(stringCasePreferences)
+	results := list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+
+		node := unwrapDoc(candidate.Node)
+
+		if guessTagFromCustomType(node) != "!!str" {
+			return Context{}, fmt.Errorf("cannot convert %v to %v case, can only operate on strings", node.Tag, prefs.Case)
+		}
+
+		newStringNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: node.Tag, Style: node.Style}
+		newStringNode.Value = changeStringCase(node.Value, prefs.Case)
+		results.PushBack(candidate.CreateReplacement(newStringNode))
+	}
+
+	return context.ChildContext(results), nil
+}
+
+// renameKeysOperator applies the expression to every map key, recursively,
+// e.g. rename_keys(to_snake)
+func renameKeysOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- renameKeysOperator")
+	results := list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		renamed, err := renameKeys(d, context, candidate, unwrapDoc(candidate.Node), expressionNode.RHS)
+		if err != nil {
+			return Context{}, err
+		}
+		results.PushBack(candidate.CreateReplacement(renamed))
+	}
+
+	return context.ChildContext(results), nil
+}
+
+func renameKeys(d *dataTreeNavigator, context Context, candidate *CandidateNode, node *yaml.Node, renameExp *ExpressionNode) (*yaml.Node, error) {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		newNode := deepCloneNoContent(node)
+		newNode.Content = make([]*yaml.Node, len(node.Content))
+		for i, child := range node.Content {
+			renamed, err := renameKeys(d, context, candidate.CreateChildInArray(i, child), child, renameExp)
+			if err != nil {
+				return nil, err
+			}
+			newNode.Content[i] = renamed
+		}
+		return newNode, nil
+	case yaml.MappingNode:
+		newNode := deepCloneNoContent(node)
+		newNode.Content = make([]*yaml.Node, len(node.Content))
+		for i := 0; i < len(node.Content); i = i + 2 {
+			key := node.Content[i]
+			value := node.Content[i+1]
+
+			newKey := key
+			if key.Tag != "!!merge" {
+				var err error
+				newKey, err = renameKey(d, context, candidate, key, renameExp)
+				if err != nil {
+					return nil, err
+				}
+			}
+			newValue, err := renameKeys(d, context, candidate.CreateChildInMap(key, value), value, renameExp)
+			if err != nil {
+				return nil, err
+			}
+			newNode.Content[i] = newKey
+			newNode.Content[i+1] = newValue
+		}
+		if err := checkRenamedKeysAreUnique(candidate, node, newNode); err != nil {
+			return nil, err
+		}
+		return newNode, nil
+	}
+	return node, nil
+}
+
+// checkRenamedKeysAreUnique makes sure renaming the keys of the original map
+// has not given two of them the same name.
+func checkRenamedKeysAreUnique(candidate *CandidateNode, original *yaml.Node, renamed *yaml.Node) error {
+	seen := map[string]*yaml.Node{}
+	for i := 0; i < len(renamed.Content); i = i + 2 {
+		newKey := renamed.Content[i]
+		if newKey.Tag == "!!merge" {
+			continue
+		}
+		id := newKey.Tag + ":" + newKey.Value
+		if otherKey, exists := seen[id]; exists {
+			return fmt.Errorf("cannot rename keys %v and %v, both would be renamed to '%v'",
+				candidate.CreateChildInMap(otherKey, otherKey).GetNicePath(),
+				candidate.CreateChildInMap(original.Content[i], original.Content[i]).GetNicePath(),
+				newKey.Value)
+		}
+		seen[id] = original.Content[i]
+	}
+	return nil
+}
+
+func renameKey(d *dataTreeNavigator, context Context, parent *CandidateNode, key *yaml.Node, renameExp *ExpressionNode) (*yaml.Node, error) {
+	keyCandidate := parent.CreateChildInMap(key, key)
+	keyCandidate.IsMapKey = true
+
+	result, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(keyCandidate), renameExp)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchingNodes.Front() == nil {
+		return key, nil
+	}
+	resultNode := unwrapDoc(result.MatchingNodes.Front().Value.(*CandidateNode).Node)
+	if resultNode.Kind != yaml.ScalarNode {
+		return nil, fmt.Errorf("cannot rename key %v to a %v, keys must be scalars", keyCandidate.GetNicePath(), resultNode.Tag)
+	}
+
+	newKey := deepCloneNoContent(key)
+	newKey.Value = resultNode.Value
+	newKey.Tag = resultNode.Tag
+	return newKey, nil
+}
@@ -0,0 +1,131 @@
+package yqlib
+
+import (
+	"testing"
+)
+
+var stringCaseOperatorScenarios = []expressionScenario{
+	{
+		description: "Convert to camel case",
+		document:    `[http_server_port, Max-Surge, SCREAMING_SNAKE]`,
+		expression:  `.[] |= to_camel`,
+		expected: []string{
+			"D0, P[], (doc)::[httpServerPort, maxSurge, screamingSnake]\n",
+		},
+	},
+	{
+		description: "Convert to pascal case",
+		document:    `[http_server_port, maxSurge]`,
+		expression:  `.[] |= to_pascal`,
+		expected: []string{
+			"D0, P[], (doc)::[HttpServerPort, MaxSurge]\n",
+		},
+	},
+	{
+		description:    "Convert to snake case",
+		subdescription: "Acronyms are kept together as a single word",
+		document:       `[HTTPServerPort, maxSurge, api-version]`,
+		expression:     `.[] |= to_snake`,
+		expected: []string{
+			"D0, P[], (doc)::[http_server_port, max_surge, api_version]\n",
+		},
+	},
+	{
+		description: "Convert to screaming snake case",
+		document:    `a: replicaCount`,
+		expression:  `.a |= (to_snake | upcase)`,
+		expected: []string{
+			"D0, P[], (doc)::a: REPLICA_COUNT\n",
+		},
+	},
+	{
+		description: "Convert to kebab case",
+		document:    `[HTTPServerPort, max_surge]`,
+		expression:  `.[] |= to_kebab`,
+		expected: []string{
+			"D0, P[], (doc)::[http-server-port, max-surge]\n",
+		},
+	},
+	{
+		description: "Convert to title case",
+		document:    `[httpServerPort, max_surge, v1Api]`,
+		expression:  `.[] |= to_title`,
+		expected: []string{
+			"D0, P[], (doc)::[Http Server Port, Max Surge, V1 Api]\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   `[!thing someValue, "", "__"]`,
+		expression: `.[] |= to_snake`,
+		expected: []string{
+			"D0, P[], (doc)::[!thing some_value, \"\", \"\"]\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `"àbcDéf" | to_snake`,
+		expected: []string{
+			"D0, P[], (!!str)::àbc_déf\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		document:      `a: 3`,
+		expression:    `.a | to_camel`,
+		expectedError: "cannot convert !!int to camel case, can only operate on strings",
+	},
+	{
+		description:    "Rename all the keys",
+		subdescription: "Applies the expression to every map key, including maps in arrays",
+		document:       "replicaCount: 1\nimage:\n  pullPolicy: Always\nextraEnv:\n  - envName: A\n",
+		expression:     `rename_keys(to_snake | upcase)`,
+		expected: []string{
+			"D0, P[], (!!map)::REPLICA_COUNT: 1\nIMAGE:\n    PULL_POLICY: Always\nEXTRA_ENV:\n    - ENV_NAME: A\n",
+		},
+	},
+	{
+		description:    "Rename the keys of part of a document",
+		subdescription: "Use the update operator to rename the keys in place",
+		document:       "config:\n  max_surge: 1\nother_key: 2\n",
+		expression:     `.config |= rename_keys(to_camel)`,
+		expected: []string{
+			"D0, P[], (doc)::config:\n    maxSurge: 1\nother_key: 2\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   "a: &x {b_c: 1}\nd:\n  <<: *x\n  e_f: 2\n",
+		expression: `rename_keys(to_camel)`,
+		expected: []string{
+			"D0, P[], (!!map)::a: &x {bC: 1}\nd:\n    !!merge <<: *x\n    eF: 2\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		document:      `a: {b: 1}`,
+		expression:    `rename_keys([])`,
+		expectedError: "cannot rename key a to a !!seq, keys must be scalars",
+	},
+	{
+		skipDoc:       true,
+		document:      `{a: {fooBar: 1, foo_bar: 2}}`,
+		expression:    `rename_keys(to_snake)`,
+		expectedError: "cannot rename keys a.fooBar and a.foo_bar, both would be renamed to 'foo_bar'",
+	},
+	{
+		skipDoc:    true,
+		document:   `{a: 1, "1": 2}`,
+		expression: `rename_keys(if . == "a" then 1 else . end)`,
+		expected: []string{
+			"D0, P[], (!!map)::{1: 1, \"1\": 2}\n",
+		},
+	},
+}
+
+func TestStringCaseOperatorScenarios(t *testing.T) {
+	for _, tt := range stringCaseOperatorScenarios {
+		testScenario(t, &tt)
+	}
+	documentOperatorScenarios(t, "string-case", stringCaseOperatorScenarios)
+}
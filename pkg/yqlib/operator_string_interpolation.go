@@ -0,0 +1,180 @@
+package yqlib
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+)
+
+// a part of an interpolated string, either literal text or an
+// expression that was embedded with \( )
+type stringInterpolationPart struct {
+	Literal    string
+	Expression *ExpressionNode
+}
+
+type stringInterpolationPreferences struct {
+	Parts []stringInterpolationPart
+}
+
+func unescapeString(value string) string {
+	value = strings.ReplaceAll(value, "\\\"", "\"")
+	return strings.ReplaceAll(value, "\\n", "\n")
+}
+
+// findInterpolationEnd returns the index of the bracket that closes the
+// interpolation starting at start, skipping over nested brackets and strings.
+func findInterpolationEnd(value string, start int) int {
+	depth := 0
+	inString := false
+	for i := start; i < len(value); i++ {
+		switch {
+		case inString && value[i] == '\\':
+			i++
+		case value[i] == '"':
+			inString = !inString
+		case inString:
+		case value[i] == '(':
+			depth++
+		case value[i] == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// parseStringInterpolation splits the string into literal text and embedded expressions.
+// A \( that is not followed by a valid expression and closing bracket is left as it is,
+// so that regular expressions with escaped brackets (e.g. "\(x\)") work as they always have.
+func parseStringInterpolation(value string) []stringInterpolationPart {
+	parts := make([]stringInterpolationPart, 0)
+	literalStart := 0
+
+	for i := 0; i < len(value)-1; i++ {
+		if value[i] != '\\' {
+			continue
+		} else if value[i+1] != '(' {
+			// some other escape sequence, e.g. \"
+			i++
+			continue
+		}
+		end := findInterpolationEnd(value, i+1)
+		if end < 0 {
+			log.Debugf("no matching ) for \\( in \"%v\", treating it as text", value)
+			i++
+			continue
+		}
+		expression, err := ExpressionParser.ParseExpression(value[i+2 : end])
+		if err != nil || expression == nil {
+			log.Debugf("\\( in \"%v\" is not followed by an expression, treating it as text", value)
+			i++
+			continue
+		}
+		if literalStart < i {
+			parts = append(parts, stringInterpolationPart{Literal: unescapeString(value[literalStart:i])})
+		}
+		parts = append(parts, stringInterpolationPart{Expression: expression})
+		literalStart = end + 1
+		i = end
+	}
+	if literalStart < len(value) {
+		parts = append(parts, stringInterpolationPart{Literal: unescapeString(value[literalStart:])})
+	}
+	return parts
+}
+
+func stringInterpolationOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- stringInterpolationOperator")
+	prefs := expressionNode.Operation.Preferences.(stringInterpolationPreferences)
+
+	if context.MatchingNodes.Len() == 0 {
+		// like other values, interpolated strings can be used without any input
+		results, err := interpolateString(d, context, nil, prefs.Parts)
+		if err != nil {
+			return Context{}, err
+		}
+		return context.ChildContext(results), nil
+	}
+
+	results := list.New()
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		candidateResults, err := interpolateString(d, context.SingleReadonlyChildContext(candidate), candidate, prefs.Parts)
+		if err != nil {
+			return Context{}, err
+		}
+		results.PushBackList(candidateResults)
+	}
+	return context.ChildContext(results), nil
+}
+
+// interpolateString returns a string for each combination of results of the
+// embedded expressions, like jq.
+func interpolateString(d *dataTreeNavigator, context Context, candidate *CandidateNode, parts []stringInterpolationPart) (*list.List, error) {
+	prefixes := []string{""}
+
+	for _, part := range parts {
+		if part.Expression == nil {
+			for i := range prefixes {
+				prefixes[i] = prefixes[i] + part.Literal
+			}
+			continue
+		}
+
+		result, err := d.GetMatchingNodes(context, part.Expression)
+		if err != nil {
+			return nil, err
+		}
+		rendered := make([]string, 0, result.MatchingNodes.Len())
+		for el := result.MatchingNodes.Front(); el != nil; el = el.Next() {
+			resultCandidate := el.Value.(*CandidateNode)
+			stringNode, err := toString(resultCandidate, unwrapDoc(resultCandidate.Node))
+			if err != nil {
+				return nil, err
+			}
+			rendered = append(rendered, stringNode.Value)
+		}
+
+		newPrefixes := make([]string, 0, len(prefixes)*len(rendered))
+		for _, prefix := range prefixes {
+			for _, value := range rendered {
+				newPrefixes = append(newPrefixes, prefix+value)
+			}
+		}
+		prefixes = newPrefixes
+	}
+
+	results := list.New()
+	for _, value := range prefixes {
+		node := createStringScalarNode(value)
+		if candidate == nil {
+			results.PushBack(&CandidateNode{Node: node})
+		} else {
+			results.PushBack(candidate.CreateReplacement(node))
+		}
+	}
+	return results, nil
+}
+
+// createStringInterpolationOperation returns nil if the string has nothing to interpolate.
+func createStringInterpolationOperation(value string) (*Operation, error) {
+	if ExpressionParser == nil {
+		return nil, fmt.Errorf("string interpolation needs the expression parser to be initialised")
+	}
+	parts := parseStringInterpolation(value)
+	hasExpression := false
+	for _, part := range parts {
+		hasExpression = hasExpression || part.Expression != nil
+	}
+	if !hasExpression {
+		return nil, nil
+	}
+	return &Operation{
+		OperationType: stringInterpolationOpType,
+		StringValue:   value,
+		Preferences:   stringInterpolationPreferences{Parts: parts},
+	}, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 
 	"gopkg.in/yaml.v3"
 )
@@ -443,3 +444,288 @@ func split(value string, spltStr string) *yaml.Node {
 
 	return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: contents}
 }
+
+type stringFunction func(value string, args []*yaml.Node) (*yaml.Node, error)
+
+// stringFunctionOperator applies the calculation to each string, the arguments
+// of the function are evaluated against that string, e.g. startswith(.prefix)
+func stringFunctionOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode, operation string, calculation stringFunction) (Context, error) {
+	log.Debugf("-- %v", operation)
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		node := unwrapDoc(candidate.Node)
+
+		if guessTagFromCustomType(node) != "!!str" {
+			return Context{}, fmt.Errorf("cannot use %v on %v, can only operate on strings", operation, node.Tag)
+		}
+
+		argExpressions := getFunctionArguments(expressionNode.RHS)
+		args := make([]*yaml.Node, len(argExpressions))
+		for i, argExpression := range argExpressions {
+			argResult, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(candidate), argExpression)
+			if err != nil {
+				return Context{}, err
+			}
+			if argResult.MatchingNodes.Front() == nil {
+				return Context{}, fmt.Errorf("%v argument %v did not return a value", operation, i+1)
+			}
+			args[i] = unwrapDoc(argResult.MatchingNodes.Front().Value.(*CandidateNode).Node)
+		}
+
+		targetNode, err := calculation(node.Value, args)
+		if err != nil {
+			return Context{}, err
+		}
+		results.PushBack(candidate.CreateReplacement(targetNode))
+	}
+
+	return context.ChildContext(results), nil
+}
+
+func getStringArgument(operation string, node *yaml.Node) (string, error) {
+	if guessTagFromCustomType(node) != "!!str" {
+		return "", fmt.Errorf("%v expects a string argument, got %v", operation, node.Tag)
+	}
+	return node.Value, nil
+}
+
+func getIntArgument(operation string, node *yaml.Node) (int, error) {
+	if guessTagFromCustomType(node) != "!!int" {
+		return 0, fmt.Errorf("%v expects an integer argument, got %v", operation, node.Tag)
+	}
+	return parseInt(node.Value)
+}
+
+func createIntScalarNode(value int) *yaml.Node {
+	return createScalarNode(value, fmt.Sprintf("%v", value))
+}
+
+func startsWithOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	return stringFunctionOperator(d, context, expressionNode, "startswith", func(value string, args []*yaml.Node) (*yaml.Node, error) {
+		prefix, err := getStringArgument("startswith", args[0])
+		if err != nil {
+			return nil, err
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprintf("%v", strings.HasPrefix(value, prefix))}, nil
+	})
+}
+
+func endsWithOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	return stringFunctionOperator(d, context, expressionNode, "endswith", func(value string, args []*yaml.Node) (*yaml.Node, error) {
+		suffix, err := getStringArgument("endswith", args[0])
+		if err != nil {
+			return nil, err
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprintf("%v", strings.HasSuffix(value, suffix))}, nil
+	})
+}
+
+func ltrimstrOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	return stringFunctionOperator(d, context, expressionNode, "ltrimstr", func(value string, args []*yaml.Node) (*yaml.Node, error) {
+		prefix, err := getStringArgument("ltrimstr", args[0])
+		if err != nil {
+			return nil, err
+		}
+		return createStringScalarNode(strings.TrimPrefix(value, prefix)), nil
+	})
+}
+
+func rtrimstrOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	return stringFunctionOperator(d, context, expressionNode, "rtrimstr", func(value string, args []*yaml.Node) (*yaml.Node, error) {
+		suffix, err := getStringArgument("rtrimstr", args[0])
+		if err != nil {
+			return nil, err
+		}
+		return createStringScalarNode(strings.TrimSuffix(value, suffix)), nil
+	})
+}
+
+// findIndices returns the (character, not byte) offsets of all the,
+// possibly overlapping, occurrences of substring.
+func findIndices(value string, substring string) []int {
+	indices := make([]int, 0)
+	if substring == "" {
+		return indices
+	}
+	runes := []rune(value)
+	subRunes := []rune(substring)
+	for i := 0; i+len(subRunes) <= len(runes); i++ {
+		if string(runes[i:i+len(subRunes)]) == substring {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+type indexPreferences struct {
+	Last bool
+}
+
+func indexOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	prefs := expressionNode.Operation.Preferences.(indexPreferences)
+	operation := "index"
+	if prefs.Last {
+		operation = "rindex"
+	}
+	return stringFunctionOperator(d, context, expressionNode, operation, func(value string, args []*yaml.Node) (*yaml.Node, error) {
+		substring, err := getStringArgument(operation, args[0])
+		if err != nil {
+			return nil, err
+		}
+		indices := findIndices(value, substring)
+		if len(indices) == 0 {
+			return createScalarNode(nil, "null"), nil
+		} else if prefs.Last {
+			return createIntScalarNode(indices[len(indices)-1]), nil
+		}
+		return createIntScalarNode(indices[0]), nil
+	})
+}
+
+func indicesOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	return stringFunctionOperator(d, context, expressionNode, "indices", func(value string, args []*yaml.Node) (*yaml.Node, error) {
+		substring, err := getStringArgument("indices", args[0])
+		if err != nil {
+			return nil, err
+		}
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, index := range findIndices(value, substring) {
+			seq.Content = append(seq.Content, createIntScalarNode(index))
+		}
+		return seq, nil
+	})
+}
+
+func repeatStringOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	return stringFunctionOperator(d, context, expressionNode, "repeat_string", func(value string, args []*yaml.Node) (*yaml.Node, error) {
+		return repeatString("repeat_string", value, args[0])
+	})
+}
+
+func repeatString(operation string, value string, countNode *yaml.Node) (*yaml.Node, error) {
+	count, err := getIntArgument(operation, countNode)
+	if err != nil {
+		return nil, err
+	} else if count < 0 {
+		return nil, fmt.Errorf("%v expects a positive number, got %v", operation, count)
+	}
+	return createStringScalarNode(strings.Repeat(value, count)), nil
+}
+
+type padPreferences struct {
+	PadStart bool
+}
+
+// ljust(width) pads the end of the string, rjust(width) pads the start. An optional
+// second argument sets the padding, which defaults to spaces.
+func padStringOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	prefs := expressionNode.Operation.Preferences.(padPreferences)
+	operation := "ljust"
+	if prefs.PadStart {
+		operation = "rjust"
+	}
+	return stringFunctionOperator(d, context, expressionNode, operation, func(value string, args []*yaml.Node) (*yaml.Node, error) {
+		if len(args) > 2 {
+			return nil, fmt.Errorf("%v expects a width and optionally the padding, e.g. %v(10; \"-\")", operation, operation)
+		}
+		width, err := getIntArgument(operation, args[0])
+		if err != nil {
+			return nil, err
+		}
+		padding := " "
+		if len(args) == 2 {
+			padding, err = getStringArgument(operation, args[1])
+			if err != nil {
+				return nil, err
+			}
+			if utf8.RuneCountInString(padding) != 1 {
+				return nil, fmt.Errorf("%v padding must be a single character, got '%v'", operation, padding)
+			}
+		}
+
+		missing := width - utf8.RuneCountInString(value)
+		if missing <= 0 {
+			return createStringScalarNode(value), nil
+		} else if prefs.PadStart {
+			return createStringScalarNode(strings.Repeat(padding, missing) + value), nil
+		}
+		return createStringScalarNode(value + strings.Repeat(padding, missing)), nil
+	})
+}
+
+// substr(start; length) works on characters, a negative start counts from
+// the end of the string.
+func substrOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	return stringFunctionOperator(d, context, expressionNode, "substr", func(value string, args []*yaml.Node) (*yaml.Node, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("substr expects a start and a length, e.g. substr(0; 3)")
+		}
+		start, err := getIntArgument("substr", args[0])
+		if err != nil {
+			return nil, err
+		}
+		length, err := getIntArgument("substr", args[1])
+		if err != nil {
+			return nil, err
+		}
+		if length < 0 {
+			return nil, fmt.Errorf("substr expects a positive length, got %v", length)
+		}
+
+		runes := []rune(value)
+		if start < 0 {
+			start = len(runes) + start
+		}
+		if start < 0 {
+			start = 0
+		} else if start > len(runes) {
+			start = len(runes)
+		}
+		end := start + length
+		if end > len(runes) {
+			end = len(runes)
+		}
+		return createStringScalarNode(string(runes[start:end])), nil
+	})
+}
+
+func explodeStringOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	return stringFunctionOperator(d, context, expressionNode, "explode", func(value string, args []*yaml.Node) (*yaml.Node, error) {
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, codepoint := range value {
+			seq.Content = append(seq.Content, createIntScalarNode(int(codepoint)))
+		}
+		return seq, nil
+	})
+}
+
+func implodeOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- implodeOperator")
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		node := unwrapDoc(candidate.Node)
+
+		if node.Kind != yaml.SequenceNode {
+			return Context{}, fmt.Errorf("cannot implode %v, can only implode arrays of codepoints", node.Tag)
+		}
+
+		var builder strings.Builder
+		for _, child := range node.Content {
+			codepoint, err := getIntArgument("implode", child)
+			if err != nil {
+				return Context{}, err
+			}
+			if !utf8.ValidRune(rune(codepoint)) {
+				return Context{}, fmt.Errorf("cannot implode %v, it is not a valid codepoint", codepoint)
+			}
+			builder.WriteRune(rune(codepoint))
+		}
+		results.PushBack(candidate.CreateReplacement(createStringScalarNode(builder.String())))
+	}
+
+	return context.ChildContext(results), nil
+}
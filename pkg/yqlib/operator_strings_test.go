@@ -39,6 +39,96 @@ var stringsOperatorScenarios = []expressionScenario{
 			"D0, P[], (!camel)::água\n",
 		},
 	},
+	{
+		skipDoc:    true,
+		document:   `ÁgUA`,
+		expression: "ascii_downcase",
+		expected: []string{
+			"D0, P[], (!!str)::água\n",
+		},
+	},
+	{
+		description: "String interpolation",
+		document:    `{name: nginx, tag: 1.23, replicas: 3}`,
+		expression:  `"\(.name):\(.tag) x\(.replicas)"`,
+		expected: []string{
+			"D0, P[], (!!str)::nginx:1.23 x3\n",
+		},
+	},
+	{
+		skipDoc:     true,
+		description: "Missing values interpolate to nothing, like with +",
+		document:    `a: cat`,
+		expression:  `"\(.a) \(.b)"`,
+		expected:    []string{},
+	},
+	{
+		description:    "String interpolation with expressions",
+		subdescription: "Embedded expressions can contain strings of their own. Maps and arrays are rendered as json.",
+		document:       `{a: {b: [1, 2]}}`,
+		expression:     `"\(.c // "none") and \(.a)"`,
+		expected: []string{
+			"D0, P[], (!!str)::none and {\"b\":[1,2]}\n",
+		},
+	},
+	{
+		description: "String interpolation for each node",
+		document:    `[{name: cat, count: 2}, {name: dog, count: 1}]`,
+		expression:  `.[] | "\(.count) x \(.name)"`,
+		expected: []string{
+			"D0, P[0], (!!str)::2 x cat\n",
+			"D0, P[1], (!!str)::1 x dog\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `"\(1, 2)-\(3)"`,
+		expected: []string{
+			"D0, P[], (!!str)::1-3\n",
+			"D0, P[], (!!str)::2-3\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   `a: cat`,
+		expression: `"say \"\(.a)\"\n\(.b // "none")"`,
+		expected: []string{
+			"D0, P[], (!!str)::say \"cat\"\nnone\n",
+		},
+	},
+	{
+		description:    "Escaped brackets in regular expressions",
+		subdescription: "A `\\(` that is not followed by an expression and a closing bracket is left as it is, so regular expressions can still match brackets.",
+		document:       `(x) and (y`,
+		expression:     `sub("\(x\)"; "x") | test("\(y")`,
+		expected: []string{
+			"D0, P[], (!!bool)::true\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   `a: "(x)"`,
+		expression: `.a | sub("\(x\)"; "y")`,
+		expected: []string{
+			"D0, P[a], (!!str)::y\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   `a: cat`,
+		expression: `"\(.a) \(x\)"`,
+		expected: []string{
+			"D0, P[], (!!str)::cat \\(x\\)\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   `a: cat`,
+		expression: `"\("nested \(.a)")"`,
+		expected: []string{
+			"D0, P[], (!!str)::nested cat\n",
+		},
+	},
 	{
 		description: "Join strings",
 		document:    `[cat, meow, 1, null, true]`,
@@ -267,6 +357,147 @@ var stringsOperatorScenarios = []expressionScenario{
 		expression: `split("; ")`,
 		expected:   []string{},
 	},
+	{
+		description: "Starts with and ends with",
+		document:    `image: quay.io/org/app:v1`,
+		expression:  `.image | [startswith("quay.io/"), endswith(":latest")]`,
+		expected: []string{
+			"D0, P[image], (!!seq)::- true\n- false\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		document:      `a: 3`,
+		expression:    `.a | startswith("3")`,
+		expectedError: "cannot use startswith on !!int, can only operate on strings",
+	},
+	{
+		skipDoc:       true,
+		document:      `a: cat`,
+		expression:    `.a | endswith(3)`,
+		expectedError: "endswith expects a string argument, got !!int",
+	},
+	{
+		skipDoc:       true,
+		document:      `a: cat`,
+		expression:    `.a | startswith(.b)`,
+		expectedError: "startswith argument 1 did not return a value",
+	},
+	{
+		description:    "Trim a prefix or suffix",
+		subdescription: "Strings that don't have the prefix (or suffix) are left as is",
+		document:       `[quay.io/org/app:v1, docker.io/app:v1]`,
+		expression:     `.[] |= (ltrimstr("quay.io/") | rtrimstr(":v1"))`,
+		expected: []string{
+			"D0, P[], (doc)::[org/app, docker.io/app]\n",
+		},
+	},
+	{
+		description:    "Find the index of substrings",
+		subdescription: "Indexes are character offsets, `index` and `rindex` return null if the substring isn't found",
+		document:       `a: quay.io/org/app`,
+		expression:     `.a | [index("/"), rindex("/"), indices("/"), index("#")]`,
+		expected: []string{
+			"D0, P[a], (!!seq)::- 7\n- 11\n- - 7\n  - 11\n- null\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `"aaa" | indices("aa")`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 0\n- 1\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `"héllo wörld" | index("w")`,
+		expected: []string{
+			"D0, P[], (!!int)::6\n",
+		},
+	},
+	{
+		description: "Repeat a string",
+		expression:  `"ab" | repeat_string(3)`,
+		expected: []string{
+			"D0, P[], (!!str)::ababab\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		expression:    `"ab" | repeat_string("3")`,
+		expectedError: "repeat_string expects an integer argument, got !!str",
+	},
+	{
+		skipDoc:    true,
+		expression: `"abc" | repeat(2)`,
+		expected: []string{
+			"D0, P[], (!!str)::abcabc\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		expression:    `"ab" | repeat_string(-1)`,
+		expectedError: "repeat_string expects a positive number, got -1",
+	},
+	{
+		description:    "Pad strings",
+		subdescription: "`ljust` pads the end of the string and `rjust` pads the start, to the given width. The padding defaults to spaces.",
+		document:       `[a, bb, cccc]`,
+		expression:     `[.[] | ljust(3)], [.[] | rjust(3; "0")]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 'a  '\n- 'bb '\n- cccc\n",
+			"D0, P[], (!!seq)::- 00a\n- 0bb\n- cccc\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		expression:    `"a" | ljust(3; "ab")`,
+		expectedError: "ljust padding must be a single character, got 'ab'",
+	},
+	{
+		description:    "Substring",
+		subdescription: "Takes the start and length, a negative start counts from the end of the string",
+		document:       `a: quay.io/org/app:v1`,
+		expression:     `.a | (substr(0; 7), substr(-2; 2))`,
+		expected: []string{
+			"D0, P[a], (!!str)::quay.io\n",
+			"D0, P[a], (!!str)::v1\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		expression: `"cat" | (substr(1; 10), substr(5; 1), substr(-10; 2))`,
+		expected: []string{
+			"D0, P[], (!!str)::at\n",
+			"D0, P[], (!!str)::\n",
+			"D0, P[], (!!str)::ca\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		expression:    `"cat" | substr(1)`,
+		expectedError: "substr expects a start and a length, e.g. substr(0; 3)",
+	},
+	{
+		description:    "Explode and implode strings",
+		subdescription: "Without arguments, `explode` converts a string into an array of unicode codepoints (rather than exploding anchors). `implode` converts them back.",
+		document:       `a: héllo`,
+		expression:     `.a | (explode, (explode | implode))`,
+		expected: []string{
+			"D0, P[a], (!!seq)::- 104\n- 233\n- 108\n- 108\n- 111\n",
+			"D0, P[a], (!!str)::héllo\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		expression:    `[104, "a"] | implode`,
+		expectedError: "implode expects an integer argument, got !!str",
+	},
+	{
+		skipDoc:       true,
+		expression:    `"a" | implode`,
+		expectedError: "cannot implode !!str, can only implode arrays of codepoints",
+	},
 }
 
 func TestStringsOperatorScenarios(t *testing.T) {
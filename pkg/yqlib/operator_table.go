@@ -0,0 +1,255 @@
+package yqlib
+
+import (
+	"container/list"
+	"fmt"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// keyedMap builds a map node, keeping track of where each key is so that
+// setting an existing key replaces its value (last one wins) in O(1).
+type keyedMap struct {
+	operation string
+	node      *yaml.Node
+	indexes   map[string]int
+}
+
+func newKeyedMap(operation string) *keyedMap {
+	return &keyedMap{operation: operation, node: &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, indexes: map[string]int{}}
+}
+
+func (m *keyedMap) get(key *yaml.Node) *yaml.Node {
+	if index, exists := m.indexes[key.Value]; exists && m.node.Content[index].Tag == key.Tag {
+		return m.node.Content[index+1]
+	}
+	return nil
+}
+
+// set errors when the key has the same value as an existing key of another type (e.g. 1 and "1"),
+// as they would be duplicate keys in the result.
+func (m *keyedMap) set(key *yaml.Node, value *yaml.Node) error {
+	if index, exists := m.indexes[key.Value]; exists {
+		existingKey := m.node.Content[index]
+		if existingKey.Tag != key.Tag {
+			existingEncoding, err := canonicalEncoding(existingKey)
+			if err != nil {
+				return err
+			}
+			keyEncoding, err := canonicalEncoding(key)
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("cannot %v both %v and %v, they would be duplicate keys", m.operation, existingEncoding, keyEncoding)
+		}
+		m.node.Content[index+1] = value
+		return nil
+	}
+	m.indexes[key.Value] = len(m.node.Content)
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: key.Tag, Value: key.Value}
+	m.node.Content = append(m.node.Content, keyNode, value)
+	return nil
+}
+
+// getTableKey evaluates the expression against the node, the result must be a single scalar.
+func getTableKey(d *dataTreeNavigator, context Context, node *yaml.Node, operation string, expressionNode *ExpressionNode) (*yaml.Node, error) {
+	result, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(&CandidateNode{Node: node}), expressionNode)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchingNodes.Len() == 0 {
+		return nil, nil
+	}
+	key := resolveAlias(unwrapDoc(result.MatchingNodes.Front().Value.(*CandidateNode).Node))
+	if key.Kind != yaml.ScalarNode {
+		return nil, fmt.Errorf("%v keys must be scalars, got %v", operation, key.Tag)
+	}
+	return key, nil
+}
+
+func getTableArray(candidate *CandidateNode, operation string) (*yaml.Node, error) {
+	node := unwrapDoc(candidate.Node)
+	if node.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("%v only works with arrays, got %v (%v)", operation, node.Tag, candidate.GetNicePath())
+	}
+	return node, nil
+}
+
+func transposeOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- transposeOperator")
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		node, err := getTableArray(candidate, "transpose")
+		if err != nil {
+			return Context{}, err
+		}
+
+		columns := 0
+		rows := make([]*yaml.Node, len(node.Content))
+		for i, row := range node.Content {
+			rows[i] = resolveAlias(row)
+			if rows[i].Kind != yaml.SequenceNode {
+				return Context{}, fmt.Errorf("transpose expects an array of arrays, but got an array containing %v", rows[i].Tag)
+			}
+			if len(rows[i].Content) > columns {
+				columns = len(rows[i].Content)
+			}
+		}
+
+		// shorter rows are padded with nulls
+		transposed := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for column := 0; column < columns; column++ {
+			newRow := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			for _, row := range rows {
+				if column < len(row.Content) {
+					newRow.Content = append(newRow.Content, row.Content[column])
+				} else {
+					newRow.Content = append(newRow.Content, createScalarNode(nil, "null"))
+				}
+			}
+			transposed.Content = append(transposed.Content, newRow)
+		}
+		results.PushBack(candidate.CreateReplacement(transposed))
+	}
+
+	return context.ChildContext(results), nil
+}
+
+// pivot(row; col; value) turns an array of records into a map of rows, each a map of columns.
+func pivotOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- pivotOperator")
+	args := getFunctionArguments(expressionNode.RHS)
+	if len(args) != 3 {
+		return Context{}, fmt.Errorf("pivot must be given a row, column and value expression, e.g. pivot(.name; .month; .sales)")
+	}
+
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		node, err := getTableArray(candidate, "pivot")
+		if err != nil {
+			return Context{}, err
+		}
+
+		pivoted := newKeyedMap("pivot by")
+		rows := map[*yaml.Node]*keyedMap{}
+
+		for _, record := range node.Content {
+			rowKey, err := getTableKey(d, context, record, "pivot", args[0])
+			if err != nil {
+				return Context{}, err
+			}
+			columnKey, err := getTableKey(d, context, record, "pivot", args[1])
+			if err != nil {
+				return Context{}, err
+			}
+			if rowKey == nil || columnKey == nil {
+				continue
+			}
+			value, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(&CandidateNode{Node: record}), args[2])
+			if err != nil {
+				return Context{}, err
+			}
+			valueNode := createScalarNode(nil, "null")
+			if value.MatchingNodes.Len() > 0 {
+				valueNode = unwrapDoc(value.MatchingNodes.Front().Value.(*CandidateNode).Node)
+			}
+
+			rowNode := pivoted.get(rowKey)
+			if rowNode == nil {
+				row := newKeyedMap("pivot by")
+				rowNode = row.node
+				rows[rowNode] = row
+				if err := pivoted.set(rowKey, rowNode); err != nil {
+					return Context{}, err
+				}
+			}
+			if err := rows[rowNode].set(columnKey, valueNode); err != nil {
+				return Context{}, err
+			}
+		}
+		results.PushBack(candidate.CreateReplacement(pivoted.node))
+	}
+
+	return context.ChildContext(results), nil
+}
+
+// index_by(f) turns an array into a map, keyed by the result of f.
+func indexByOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- indexByOperator")
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		node, err := getTableArray(candidate, "index_by")
+		if err != nil {
+			return Context{}, err
+		}
+
+		indexed := newKeyedMap("index by")
+		for _, child := range node.Content {
+			key, err := getTableKey(d, context, child, "index_by", expressionNode.RHS)
+			if err != nil {
+				return Context{}, err
+			}
+			// like array_to_map, elements without a key are skipped
+			if key == nil || key.Tag == "!!null" {
+				continue
+			}
+			if err := indexed.set(key, child); err != nil {
+				return Context{}, err
+			}
+		}
+		results.PushBack(candidate.CreateReplacement(indexed.node))
+	}
+
+	return context.ChildContext(results), nil
+}
+
+// to_array_by(keyname) is the inverse of index_by, it turns a map of maps into an
+// array, setting keyname on each entry to its key.
+func toArrayByOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- toArrayByOperator")
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		node := unwrapDoc(candidate.Node)
+		if node.Kind != yaml.MappingNode {
+			return Context{}, fmt.Errorf("to_array_by only works with maps, got %v (%v)", node.Tag, candidate.GetNicePath())
+		}
+
+		keyNameNode, err := getSingleArgument(d, context, candidate, "to_array_by", expressionNode.RHS)
+		if err != nil {
+			return Context{}, err
+		}
+		keyName, err := getStringArgument("to_array_by", keyNameNode)
+		if err != nil {
+			return Context{}, err
+		}
+
+		array := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for i := 0; i < len(node.Content); i = i + 2 {
+			key := node.Content[i]
+			value := resolveAlias(node.Content[i+1])
+			if value.Kind != yaml.MappingNode {
+				return Context{}, fmt.Errorf("to_array_by expects a map of maps, but %v is a %v", key.Value, value.Tag)
+			}
+
+			entry := deepCloneNoContent(value)
+			entry.Content = []*yaml.Node{createStringScalarNode(keyName), deepClone(key)}
+			for j := 0; j < len(value.Content); j = j + 2 {
+				if value.Content[j].Value != keyName {
+					entry.Content = append(entry.Content, value.Content[j], value.Content[j+1])
+				}
+			}
+			array.Content = append(array.Content, entry)
+		}
+		results.PushBack(candidate.CreateReplacement(array))
+	}
+
+	return context.ChildContext(results), nil
+}
@@ -0,0 +1,117 @@
+package yqlib
+
+import (
+	"testing"
+)
+
+var tableOperatorScenarios = []expressionScenario{
+	{
+		description:    "Transpose",
+		subdescription: "Shorter rows are padded with null.",
+		document:       "[[a, b, c], [1, 2]]",
+		expression:     `transpose`,
+		expected: []string{
+			"D0, P[], (!!seq)::- - a\n  - 1\n- - b\n  - 2\n- - c\n  - null\n",
+		},
+	},
+	{
+		description:    "Pivot",
+		subdescription: "Creates a map of rows, each a map of columns to values.",
+		document:       "- {name: apples, month: jan, sales: 10}\n- {name: apples, month: feb, sales: 12}\n- {name: pears, month: jan, sales: 7}\n",
+		expression:     `pivot(.name; .month; .sales)`,
+		expected: []string{
+			"D0, P[], (!!map)::apples:\n    jan: 10\n    feb: 12\npears:\n    jan: 7\n",
+		},
+	},
+	{
+		description:    "Index by",
+		subdescription: "Creates a map keyed by the expression. When more than one element has the same key, the last one wins.",
+		document:       "- {name: web, port: 80}\n- {name: db, port: 5432}\n",
+		expression:     `index_by(.name)`,
+		expected: []string{
+			"D0, P[], (!!map)::web: {name: web, port: 80}\ndb: {name: db, port: 5432}\n",
+		},
+	},
+	{
+		description:    "To array by",
+		subdescription: "The inverse of `index_by`, each key is set as a field of its value.",
+		document:       "web: {port: 80}\ndb: {port: 5432}\n",
+		expression:     `to_array_by("name")`,
+		expected: []string{
+			"D0, P[], (!!seq)::- {name: web, port: 80}\n- {name: db, port: 5432}\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   "[{id: 1, v: a}, {v: b}, {id: 1, v: c}]",
+		expression: `index_by(.id)`,
+		expected: []string{
+			"D0, P[], (!!map)::1: {id: 1, v: c}\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		document:      `[{id: 1, v: a}, {id: "1", v: b}]`,
+		expression:    `index_by(.id)`,
+		expectedError: `cannot index by both 1 and "1", they would be duplicate keys`,
+	},
+	{
+		skipDoc:       true,
+		document:      `[{name: a, month: 1, sales: 1}, {name: a, month: "1", sales: 2}]`,
+		expression:    `pivot(.name; .month; .sales)`,
+		expectedError: `cannot pivot by both 1 and "1", they would be duplicate keys`,
+	},
+	{
+		skipDoc:       true,
+		document:      `[{name: true, month: jan, sales: 1}, {name: "true", month: jan, sales: 2}]`,
+		expression:    `pivot(.name; .month; .sales)`,
+		expectedError: `cannot pivot by both true and "true", they would be duplicate keys`,
+	},
+	{
+		skipDoc:    true,
+		document:   "web: {name: old, port: 80}\n",
+		expression: `to_array_by("name")`,
+		expected: []string{
+			"D0, P[], (!!seq)::- {name: web, port: 80}\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   "[]",
+		expression: `transpose`,
+		expected: []string{
+			"D0, P[], (!!seq)::[]\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		document:      "[[a], b]",
+		expression:    `transpose`,
+		expectedError: "transpose expects an array of arrays, but got an array containing !!str",
+	},
+	{
+		skipDoc:       true,
+		document:      "[{a: [1]}]",
+		expression:    `index_by(.a)`,
+		expectedError: "index_by keys must be scalars, got !!seq",
+	},
+	{
+		skipDoc:       true,
+		document:      "a: cat",
+		expression:    `to_array_by("name")`,
+		expectedError: "to_array_by expects a map of maps, but a is a !!str",
+	},
+	{
+		skipDoc:       true,
+		document:      "[]",
+		expression:    `pivot(.a; .b)`,
+		expectedError: "pivot must be given a row, column and value expression, e.g. pivot(.name; .month; .sales)",
+	},
+}
+
+func TestTableOperatorScenarios(t *testing.T) {
+	for _, tt := range tableOperatorScenarios {
+		testScenario(t, &tt)
+	}
+	documentOperatorScenarios(t, "table", tableOperatorScenarios)
+}
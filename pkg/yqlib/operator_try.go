@@ -0,0 +1,72 @@
+package yqlib
+
+import (
+	"container/list"
+	"fmt"
+)
+
+func tryOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- tryOperator")
+	return tryWithHandler(d, context, expressionNode.RHS, nil)
+}
+
+func catchOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- catchOperator")
+	// try <exp> catch <handler>
+	// is parsed as
+	// TRY(<exp>) CATCH <handler>
+	if expressionNode.LHS.Operation.OperationType != tryOpType {
+		return Context{}, fmt.Errorf("catch must follow a try, e.g. try .a catch \"default\"")
+	}
+	return tryWithHandler(d, context, expressionNode.LHS.RHS, expressionNode.RHS)
+}
+
+func tryWithHandler(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode, handler *ExpressionNode) (Context, error) {
+	var evaluateAllTogether = true
+	for matchEl := context.MatchingNodes.Front(); matchEl != nil; matchEl = matchEl.Next() {
+		evaluateAllTogether = evaluateAllTogether && matchEl.Value.(*CandidateNode).EvaluateTogether
+		if !evaluateAllTogether {
+			break
+		}
+	}
+
+	if evaluateAllTogether && context.MatchingNodes.Len() > 0 {
+		results, err := tryCandidates(d, context, expressionNode, handler, context.MatchingNodes.Front().Value.(*CandidateNode))
+		if err != nil {
+			return Context{}, err
+		}
+		return context.ChildContext(results), nil
+	}
+
+	// errors are caught for each matching node, so that one bad node
+	// doesn't stop the others from being processed.
+	var results = list.New()
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		candidateResults, err := tryCandidates(d, context.SingleChildContext(candidate), expressionNode, handler, candidate)
+		if err != nil {
+			return Context{}, err
+		}
+		results.PushBackList(candidateResults)
+	}
+	return context.ChildContext(results), nil
+}
+
+func tryCandidates(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode, handler *ExpressionNode, owner *CandidateNode) (*list.List, error) {
+	result, err := d.GetMatchingNodes(context, expressionNode)
+	if err == nil {
+		return result.MatchingNodes, nil
+	}
+	log.Debugf("try caught: %v", err.Error())
+	if handler == nil {
+		return list.New(), nil
+	}
+
+	// the handler is given the error message
+	errorCandidate := owner.CreateReplacement(createStringScalarNode(err.Error()))
+	handled, err := d.GetMatchingNodes(context.SingleReadonlyChildContext(errorCandidate), handler)
+	if err != nil {
+		return nil, err
+	}
+	return handled.MatchingNodes, nil
+}
@@ -0,0 +1,92 @@
+package yqlib
+
+import (
+	"testing"
+)
+
+var tryOperatorScenarios = []expressionScenario{
+	{
+		description:    "Catch an error",
+		subdescription: "The error message is given to the catch expression",
+		document:       `a: hello`,
+		expression:     `try error("something went wrong") catch ("Caught: " + .)`,
+		expected: []string{
+			"D0, P[], (!!str)::Caught: something went wrong\n",
+		},
+	},
+	{
+		description:    "Try without catch",
+		subdescription: "Errors are ignored",
+		document:       `[1, 2, 3]`,
+		expression:     `[.[] | try (select(. == 2) | error("no twos allowed"))]`,
+		expected: []string{
+			"D0, P[], (!!seq)::[]\n",
+		},
+	},
+	{
+		description:    "Errors are caught per node",
+		subdescription: "One bad node doesn't stop the others from being processed",
+		document:       `[{name: cat, legs: 4}, {name: snake}, {name: bird, legs: 2}]`,
+		expression:     `.[] |= try (if has("legs") then . else error(.name + " has no legs") end) catch {"error": .}`,
+		expected: []string{
+			"D0, P[], (doc)::[{name: cat, legs: 4}, {error: snake has no legs}, {name: bird, legs: 2}]\n",
+		},
+	},
+	{
+		description:    "Optional operator",
+		subdescription: "`?` is a shorthand for try without a catch, and can be used after any expression",
+		document:       `[{a: cat}, {a: dog}]`,
+		expression:     `[.[] | (if .a == "cat" then error("no cats") else .a end)?]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- dog\n",
+		},
+	},
+	{
+		description: "Optional operator on a function",
+		skipDoc:     true,
+		document:    `a: hello`,
+		expression:  `error("bad")?, .a`,
+		expected: []string{
+			"D0, P[a], (!!str)::hello\n",
+		},
+	},
+	{
+		description: "Optional operator followed by traverse",
+		skipDoc:     true,
+		document:    `a: {b: hello}`,
+		expression:  `(.a)?.b`,
+		expected: []string{
+			"D0, P[a b], (!!str)::hello\n",
+		},
+	},
+	{
+		description: "Try binds tightly",
+		skipDoc:     true,
+		document:    `a: hello`,
+		expression:  `try .a catch "x" | . + "!"`,
+		expected: []string{
+			"D0, P[a], (!!str)::hello!\n",
+		},
+	},
+	{
+		description:   "Error in the catch handler",
+		skipDoc:       true,
+		document:      `a: hello`,
+		expression:    `try error("bad") catch error("worse: " + .)`,
+		expectedError: "worse: bad",
+	},
+	{
+		description:   "Catch without try",
+		skipDoc:       true,
+		document:      `a: hello`,
+		expression:    `.a catch "x"`,
+		expectedError: "catch must follow a try, e.g. try .a catch \"default\"",
+	},
+}
+
+func TestTryOperatorScenarios(t *testing.T) {
+	for _, tt := range tryOperatorScenarios {
+		testScenario(t, &tt)
+	}
+	documentOperatorScenarios(t, "try-catch", tryOperatorScenarios)
+}
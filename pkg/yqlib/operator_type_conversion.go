@@ -0,0 +1,133 @@
+package yqlib
+
+import (
+	"container/list"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+type conversionFunction func(candidate *CandidateNode, node *yaml.Node) (*yaml.Node, error)
+
+func conversionOperator(context Context, conversion conversionFunction) (Context, error) {
+	results := list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+		result, err := conversion(candidate, unwrapDoc(candidate.Node))
+		if err != nil {
+			return Context{}, err
+		}
+		results.PushBack(candidate.CreateReplacement(result))
+	}
+
+	return context.ChildContext(results), nil
+}
+
+// conversions work on the value of an alias, rather than the alias itself
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+func toNumberOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- toNumberOperator")
+	return conversionOperator(context, toNumber)
+}
+
+func toNumber(candidate *CandidateNode, node *yaml.Node) (*yaml.Node, error) {
+	node = resolveAlias(node)
+	if node.Kind != yaml.ScalarNode {
+		return nil, fmt.Errorf("cannot convert %v (%v) to a number, only scalars can be converted", candidate.GetNiceTag(), candidate.GetNicePath())
+	}
+
+	switch guessTagFromCustomType(node) {
+	case "!!int", "!!float":
+		return node, nil
+	case "!!str":
+		value := strings.TrimSpace(node.Value)
+		if _, _, err := parseInt64(value); err == nil {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: value}, nil
+		}
+		// ParseFloat also accepts Inf and NaN, which are not valid yaml floats
+		if number, err := strconv.ParseFloat(value, 64); err == nil && !math.IsInf(number, 0) && !math.IsNaN(number) {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: value}, nil
+		}
+		return nil, fmt.Errorf("cannot convert '%v' (%v) to a number", node.Value, candidate.GetNicePath())
+	}
+	return nil, fmt.Errorf("cannot convert %v (%v) to a number", node.Tag, candidate.GetNicePath())
+}
+
+func toStringOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- toStringOperator")
+	return conversionOperator(context, toString)
+}
+
+func toString(candidate *CandidateNode, node *yaml.Node) (*yaml.Node, error) {
+	node = resolveAlias(node)
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Tag == "!!null" {
+			return createStringScalarNode("null"), nil
+		}
+		return createStringScalarNode(node.Value), nil
+	}
+
+	// like jq, maps and arrays are encoded as json
+	value, err := encodeToString(candidate.CreateReplacement(node), encoderPreferences{format: JSONOutputFormat, indent: 0})
+	if err != nil {
+		return nil, err
+	}
+	return createStringScalarNode(strings.TrimSuffix(value, "\n")), nil
+}
+
+func toBoolOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- toBoolOperator")
+	return conversionOperator(context, toBool)
+}
+
+func toBool(candidate *CandidateNode, node *yaml.Node) (*yaml.Node, error) {
+	node = resolveAlias(node)
+	if node.Kind != yaml.ScalarNode {
+		return nil, fmt.Errorf("cannot convert %v (%v) to a boolean, only scalars can be converted", candidate.GetNiceTag(), candidate.GetNicePath())
+	}
+
+	switch guessTagFromCustomType(node) {
+	case "!!bool":
+		return node, nil
+	case "!!str":
+		// unlike strconv.ParseBool, only true and false are accepted
+		value := strings.ToLower(strings.TrimSpace(node.Value))
+		if value != "true" && value != "false" {
+			return nil, fmt.Errorf("cannot convert '%v' (%v) to a boolean", node.Value, candidate.GetNicePath())
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: value}, nil
+	}
+	return nil, fmt.Errorf("cannot convert %v (%v) to a boolean", node.Tag, candidate.GetNicePath())
+}
+
+func getKindOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- getKindOperator")
+	return conversionOperator(context, func(candidate *CandidateNode, node *yaml.Node) (*yaml.Node, error) {
+		return createStringScalarNode(kindToString(node.Kind)), nil
+	})
+}
+
+func kindToString(kind yaml.Kind) string {
+	switch kind {
+	case yaml.MappingNode:
+		return "map"
+	case yaml.SequenceNode:
+		return "seq"
+	case yaml.AliasNode:
+		return "alias"
+	case yaml.DocumentNode:
+		return "document"
+	}
+	return "scalar"
+}
@@ -0,0 +1,161 @@
+package yqlib
+
+import (
+	"testing"
+)
+
+var typeConversionOperatorScenarios = []expressionScenario{
+	{
+		description: "Convert strings to numbers",
+		document:    `{port: "8080", ratio: "0.75", mask: "0xFF"}`,
+		expression:  `.[] |= to_number`,
+		expected: []string{
+			"D0, P[], (doc)::{port: 8080, ratio: 0.75, mask: 0xFF}\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   `{port: "8080"}`,
+		expression: `.port | to_number | tag`,
+		expected: []string{
+			"D0, P[port], (!!str)::!!int\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   `{ratio: 0.5}`,
+		expression: `.ratio | tonumber`,
+		expected: []string{
+			"D0, P[ratio], (!!float)::0.5\n",
+		},
+	},
+	{
+		description:    "Invalid numbers are an error",
+		subdescription: "Unlike setting the tag, the value is validated",
+		document:       `{port: http}`,
+		expression:     `.port | to_number`,
+		expectedError:  "cannot convert 'http' (port) to a number",
+	},
+	{
+		skipDoc:       true,
+		document:      `{port: true}`,
+		expression:    `.port | to_number`,
+		expectedError: "cannot convert !!bool (port) to a number",
+	},
+	{
+		skipDoc:       true,
+		document:      `{a: Inf}`,
+		expression:    `.a | to_number`,
+		expectedError: "cannot convert 'Inf' (a) to a number",
+	},
+	{
+		skipDoc:       true,
+		document:      `{a: NaN}`,
+		expression:    `.a | to_number`,
+		expectedError: "cannot convert 'NaN' (a) to a number",
+	},
+	{
+		skipDoc:       true,
+		document:      `{a: infinity}`,
+		expression:    `.a | to_number`,
+		expectedError: "cannot convert 'infinity' (a) to a number",
+	},
+	{
+		skipDoc:       true,
+		document:      `{a: "-inf"}`,
+		expression:    `.a | to_number`,
+		expectedError: "cannot convert '-inf' (a) to a number",
+	},
+	{
+		skipDoc:       true,
+		document:      `{port: [80]}`,
+		expression:    `.port | to_number`,
+		expectedError: "cannot convert !!seq (port) to a number, only scalars can be converted",
+	},
+	{
+		description: "Convert to strings",
+		document:    `[8080, true, null, cat]`,
+		expression:  `.[] |= to_string`,
+		expected: []string{
+			"D0, P[], (doc)::[\"8080\", \"true\", \"null\", cat]\n",
+		},
+	},
+	{
+		description:    "Convert maps and arrays to strings",
+		subdescription: "Like jq, these are encoded as json",
+		document:       `{a: {b: [1, two]}}`,
+		expression:     `.a | to_string`,
+		expected: []string{
+			"D0, P[a], (!!str)::{\"b\":[1,\"two\"]}\n",
+		},
+	},
+	{
+		skipDoc:    true,
+		document:   `{a: &x 3, b: *x}`,
+		expression: `.b | to_string`,
+		expected: []string{
+			"D0, P[b], (!!str)::3\n",
+		},
+	},
+	{
+		description:    "Convert to booleans",
+		subdescription: "Only the strings `true` and `false` can be converted, in any case",
+		document:       `["true", "False", true]`,
+		expression:     `.[] |= to_bool`,
+		expected: []string{
+			"D0, P[], (doc)::[true, false, true]\n",
+		},
+	},
+	{
+		skipDoc:       true,
+		document:      `{enabled: "t"}`,
+		expression:    `.enabled | to_bool`,
+		expectedError: "cannot convert 't' (enabled) to a boolean",
+	},
+	{
+		skipDoc:       true,
+		document:      `{enabled: 1}`,
+		expression:    `.enabled | to_bool`,
+		expectedError: "cannot convert !!int (enabled) to a boolean",
+	},
+	{
+		skipDoc:       true,
+		document:      `{enabled: maybe}`,
+		expression:    `.enabled | to_bool`,
+		expectedError: "cannot convert 'maybe' (enabled) to a boolean",
+	},
+	{
+		skipDoc:       true,
+		document:      `{enabled: 1.5}`,
+		expression:    `.enabled | to_bool`,
+		expectedError: "cannot convert !!float (enabled) to a boolean",
+	},
+	{
+		description: "Get kind",
+		document:    `{a: cat, b: [1], c: {d: e}, f: &anchor g, h: *anchor}`,
+		expression:  `.[] | kind`,
+		expected: []string{
+			"D0, P[a], (!!str)::scalar\n",
+			"D0, P[b], (!!str)::seq\n",
+			"D0, P[c], (!!str)::map\n",
+			"D0, P[f], (!!str)::scalar\n",
+			"D0, P[h], (!!str)::alias\n",
+		},
+	},
+	{
+		description:    "Kind ignores custom tags",
+		subdescription: "Whereas `tag` returns the custom tag",
+		document:       `{a: !thing {b: c}}`,
+		expression:     `.a | [kind, tag]`,
+		expected: []string{
+			"D0, P[a], (!!seq)::- map\n- '!thing'\n",
+		},
+	},
+}
+
+func TestTypeConversionOperatorScenarios(t *testing.T) {
+	for _, tt := range typeConversionOperatorScenarios {
+		testScenario(t, &tt)
+	}
+	documentOperatorScenarios(t, "type-conversion", typeConversionOperatorScenarios)
+}
@@ -9,7 +9,13 @@ func getVariableOperator(d *dataTreeNavigator, context Context, expressionNode *
 	variableName := expressionNode.Operation.StringValue
 	log.Debug("getVariableOperator %v", variableName)
 	result := context.GetVariable(variableName)
-	if result == nil {
+	if result == nil && variableName == "ENV" {
+		var err error
+		result, err = envVariable()
+		if err != nil {
+			return Context{}, err
+		}
+	} else if result == nil {
 		result = list.New()
 	}
 	return context.ChildContext(result), nil
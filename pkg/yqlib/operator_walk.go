@@ -0,0 +1,95 @@
+package yqlib
+
+import (
+	"container/list"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func walkOperator(d *dataTreeNavigator, context Context, expressionNode *ExpressionNode) (Context, error) {
+	log.Debugf("-- walkOperator")
+
+	var results = list.New()
+
+	for el := context.MatchingNodes.Front(); el != nil; el = el.Next() {
+		candidate := el.Value.(*CandidateNode)
+
+		if candidate.Node.Kind == yaml.DocumentNode {
+			walked, err := walk(d, context, candidate.CreateReplacement(unwrapDoc(candidate.Node)), expressionNode.RHS)
+			if err != nil {
+				return Context{}, err
+			}
+			// keep the document wrapper so top level comments are not lost.
+			for _, result := range walked {
+				docNode := deepCloneNoContent(candidate.Node)
+				docNode.Content = []*yaml.Node{result.Node}
+				results.PushBack(candidate.CreateReplacementWithDocWrappers(docNode))
+			}
+			continue
+		}
+
+		walked, err := walk(d, context, candidate, expressionNode.RHS)
+		if err != nil {
+			return Context{}, err
+		}
+		for _, result := range walked {
+			results.PushBack(result)
+		}
+	}
+
+	return context.ChildContext(results), nil
+}
+
+// walk applies the expression to all the children of the candidate first, and then to
+// the (rebuilt) candidate itself. Like jq, if the expression returns nothing for a map
+// value then that entry is removed, and arrays collect all the results for each element.
+func walk(d *dataTreeNavigator, context Context, candidate *CandidateNode, expression *ExpressionNode) ([]*CandidateNode, error) {
+	node := candidate.Node
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		newNode := deepCloneNoContent(node)
+		for index := 0; index < len(node.Content); index = index + 2 {
+			key := node.Content[index]
+			value := node.Content[index+1]
+			walkedValues, err := walk(d, context, candidate.CreateChildInMap(key, value), expression)
+			if err != nil {
+				return nil, err
+			}
+			if len(walkedValues) > 0 {
+				newNode.Content = append(newNode.Content, key, walkedValues[0].Node)
+			}
+		}
+		candidate = candidate.CreateReplacement(newNode)
+	case yaml.SequenceNode:
+		newNode := deepCloneNoContent(node)
+		for index, child := range node.Content {
+			walkedValues, err := walk(d, context, candidate.CreateChildInArray(index, child), expression)
+			if err != nil {
+				return nil, err
+			}
+			for _, walkedValue := range walkedValues {
+				newNode.Content = append(newNode.Content, walkedValue.Node)
+			}
+		}
+		candidate = candidate.CreateReplacement(newNode)
+	default:
+		// the expression may update the node in place, so give it a copy
+		candidate = candidate.CreateReplacement(deepClone(node))
+	}
+
+	// the candidate is a copy, so the expression can always update it (e.g. to add keys to maps),
+	// even when walk itself is evaluated read only
+	walkContext := context.SingleChildContext(candidate)
+	walkContext.DontAutoCreate = false
+	result, err := d.GetMatchingNodes(walkContext, expression)
+	if err != nil {
+		return nil, err
+	}
+
+	walked := make([]*CandidateNode, 0, result.MatchingNodes.Len())
+	for el := result.MatchingNodes.Front(); el != nil; el = el.Next() {
+		walked = append(walked, el.Value.(*CandidateNode))
+	}
+	return walked, nil
+}
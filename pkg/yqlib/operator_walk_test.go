@@ -0,0 +1,102 @@
+package yqlib
+
+import (
+	"testing"
+)
+
+var walkOperatorScenarios = []expressionScenario{
+	{
+		description: "Sort all arrays",
+		document:    "a: [3, 1, 2]\nb:\n  c: [z, x, y]\n",
+		expression:  `walk(if tag == "!!seq" then sort else . end)`,
+		expected: []string{
+			"D0, P[], (doc)::a: [1, 2, 3]\nb:\n    c: [x, y, z]\n",
+		},
+	},
+	{
+		description: "Replace nulls with a default value",
+		document:    "a: null\nb: [1, ~]\n",
+		expression:  `walk(. // "default")`,
+		expected: []string{
+			"D0, P[], (doc)::a: default\nb: [1, default]\n",
+		},
+	},
+	{
+		description:    "Remove all null values",
+		subdescription: "Map entries are removed when the expression returns nothing.",
+		document:       "a: null\nb:\n  c: cat\n  d: null\ne: [1, null, 2]\n",
+		expression:     `walk(select(tag != "!!null"))`,
+		expected: []string{
+			"D0, P[], (doc)::b:\n    c: cat\ne: [1, 2]\n",
+		},
+	},
+	{
+		description:    "Comments, styles and anchors are kept",
+		subdescription: "On nodes that are returned unchanged.",
+		document:       "# things\na: &cool [1, 2] # nums\nb: \"cat\"\n",
+		expression:     `walk(if tag == "!!int" then . + 1 else . end)`,
+		expected: []string{
+			"D0, P[], (doc)::# things\na: &cool [2, 3] # nums\nb: \"cat\"\n",
+		},
+	},
+	{
+		description: "Children are walked before their parents",
+		document:    "a: {b: {c: 1}}",
+		expression:  `[.a | walk(if tag == "!!map" then length else . end)]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- 1\n",
+		},
+	},
+	{
+		description: "Walk a scalar",
+		skipDoc:     true,
+		document:    "a: 1",
+		expression:  `.a | walk(. * 2)`,
+		expected: []string{
+			"D0, P[a], (!!int)::2\n",
+		},
+	},
+	{
+		description: "Walk can return multiple results",
+		skipDoc:     true,
+		document:    "a: [1, 2]",
+		expression:  `.a | walk(if tag == "!!int" then (., . * 10) else . end)`,
+		expected: []string{
+			"D0, P[a], (!!seq)::[1, 10, 2, 20]\n",
+		},
+	},
+	{
+		description: "Walk does not modify the original document",
+		skipDoc:     true,
+		document:    "a: [1, 2]",
+		expression:  `(.a | walk(if tag == "!!seq" then reverse else . end)) as $x | .`,
+		expected: []string{
+			"D0, P[], (doc)::a: [1, 2]\n",
+		},
+	},
+	{
+		description:    "Add a key to every map",
+		subdescription: "The expression can update the node it is given, e.g. to add keys",
+		document:       `{a: {b: 1}}`,
+		expression:     `walk(if tag == "!!map" then .z = 1 else . end)`,
+		expected: []string{
+			"D0, P[], (doc)::{a: {b: 1, z: 1}, z: 1}\n",
+		},
+	},
+	{
+		description: "Updates by walk do not modify the original document",
+		skipDoc:     true,
+		document:    `{a: {b: 1, c: [2]}}`,
+		expression:  `(.a | walk(if tag == "!!int" then (. = 5) elif tag == "!!map" then (.z = 1) else . end)) as $x | [., $x]`,
+		expected: []string{
+			"D0, P[], (!!seq)::- {a: {b: 1, c: [2]}}\n- {b: 5, c: [5], z: 1}\n",
+		},
+	},
+}
+
+func TestWalkOperatorScenarios(t *testing.T) {
+	for _, tt := range walkOperatorScenarios {
+		testScenario(t, &tt)
+	}
+	documentOperatorScenarios(t, "walk", walkOperatorScenarios)
+}
@@ -27,6 +27,18 @@ const (
 	TSVOutputFormat
 	XMLOutputFormat
 	Base64OutputFormat
+	Base32OutputFormat
+	HexOutputFormat
+	ShOutputFormat
+	URIOutputFormat
+	HTMLOutputFormat
+	TextOutputFormat
+	MD5OutputFormat
+	SHA1OutputFormat
+	SHA256OutputFormat
+	SHA512OutputFormat
+	GzipOutputFormat
+	ZlibOutputFormat
 )
 
 func OutputFormatFromString(format string) (PrinterOutputFormat, error) {
@@ -2,6 +2,7 @@ package yqlib
 
 import (
 	"bufio"
+	"container/list"
 	"fmt"
 	"io"
 	"os"
@@ -33,9 +34,12 @@ type multiPrintWriter struct {
 	nameExpression *ExpressionNode
 	extension      string
 	index          int
+	variables      map[string]*list.List
 }
 
-func NewMultiPrinterWriter(expression *ExpressionNode, format PrinterOutputFormat) PrinterWriter {
+// NewMultiPrinterWriter writes each result to the file named by the expression, which can use
+// $index as well as the given variables.
+func NewMultiPrinterWriter(expression *ExpressionNode, format PrinterOutputFormat, variables map[string]*list.List) PrinterWriter {
 	extension := "yml"
 
 	switch format {
@@ -50,6 +54,7 @@ func NewMultiPrinterWriter(expression *ExpressionNode, format PrinterOutputForma
 		extension:      extension,
 		treeNavigator:  NewDataTreeNavigator(),
 		index:          0,
+		variables:      variables,
 	}
 }
 
@@ -59,7 +64,7 @@ func (sp *multiPrintWriter) GetWriter(node *CandidateNode) (*bufio.Writer, error
 	indexVariableNode := yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: fmt.Sprintf("%v", sp.index)}
 	indexVariableCandidate := CandidateNode{Node: &indexVariableNode}
 
-	context := Context{MatchingNodes: node.AsList()}
+	context := newEvaluationContext(node.AsList(), sp.variables)
 	context.SetVariable("index", indexVariableCandidate.AsList())
 	result, err := sp.treeNavigator.GetMatchingNodes(context, sp.nameExpression)
 	if err != nil {
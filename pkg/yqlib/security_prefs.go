@@ -0,0 +1,13 @@
+package yqlib
+
+import "errors"
+
+type SecurityPreferences struct {
+	// DisableEnvOps stops expressions from reading environment variables
+	// (env, strenv, envsubst, getenv and $ENV), e.g. when running untrusted expressions.
+	DisableEnvOps bool
+}
+
+var ConfiguredSecurityPreferences = SecurityPreferences{}
+
+var errEnvOpsDisabled = errors.New("env operations have been disabled")
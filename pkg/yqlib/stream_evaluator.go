@@ -17,17 +17,25 @@ type StreamEvaluator interface {
 	Evaluate(filename string, reader io.Reader, node *ExpressionNode, printer Printer, decoder Decoder) (uint, error)
 	EvaluateFiles(expression string, filenames []string, printer Printer, decoder Decoder) error
 	EvaluateNew(expression string, printer Printer) error
+
+	// SetVariables sets variables (e.g. from command line arguments) that the expression can use as $name
+	SetVariables(variables map[string]*list.List)
 }
 
 type streamEvaluator struct {
 	treeNavigator DataTreeNavigator
 	fileIndex     int
+	variables     map[string]*list.List
 }
 
 func NewStreamEvaluator() StreamEvaluator {
 	return &streamEvaluator{treeNavigator: NewDataTreeNavigator()}
 }
 
+func (s *streamEvaluator) SetVariables(variables map[string]*list.List) {
+	s.variables = variables
+}
+
 func (s *streamEvaluator) EvaluateNew(expression string, printer Printer) error {
 	node, err := ExpressionParser.ParseExpression(expression)
 	if err != nil {
@@ -42,7 +50,7 @@ func (s *streamEvaluator) EvaluateNew(expression string, printer Printer) error
 	inputList := list.New()
 	inputList.PushBack(candidateNode)
 
-	result, errorParsing := s.treeNavigator.GetMatchingNodes(Context{MatchingNodes: inputList}, node)
+	result, errorParsing := s.treeNavigator.GetMatchingNodes(newEvaluationContext(inputList, s.variables), node)
 	if errorParsing != nil {
 		return errorParsing
 	}
@@ -105,7 +113,7 @@ func (s *streamEvaluator) Evaluate(filename string, reader io.Reader, node *Expr
 		inputList := list.New()
 		inputList.PushBack(candidateNode)
 
-		result, errorParsing := s.treeNavigator.GetMatchingNodes(Context{MatchingNodes: inputList}, node)
+		result, errorParsing := s.treeNavigator.GetMatchingNodes(newEvaluationContext(inputList, s.variables), node)
 		if errorParsing != nil {
 			return currentIndex, errorParsing
 		}
@@ -12,11 +12,15 @@ import (
 
 type StringEvaluator interface {
 	Evaluate(expression string, input string, encoder Encoder, decoder Decoder) (string, error)
+
+	// SetVariables sets variables (e.g. from command line arguments) that the expression can use as $name
+	SetVariables(variables map[string]*list.List)
 }
 
 type stringEvaluator struct {
 	treeNavigator DataTreeNavigator
 	fileIndex     int
+	variables     map[string]*list.List
 }
 
 func NewStringEvaluator() StringEvaluator {
@@ -25,6 +29,10 @@ func NewStringEvaluator() StringEvaluator {
 	}
 }
 
+func (s *stringEvaluator) SetVariables(variables map[string]*list.List) {
+	s.variables = variables
+}
+
 func (s *stringEvaluator) Evaluate(expression string, input string, encoder Encoder, decoder Decoder) (string, error) {
 
 	// Use bytes.Buffer for output of string
@@ -59,7 +67,7 @@ func (s *stringEvaluator) Evaluate(expression string, input string, encoder Enco
 		inputList := list.New()
 		inputList.PushBack(candidateNode)
 
-		result, errorParsing := s.treeNavigator.GetMatchingNodes(Context{MatchingNodes: inputList}, node)
+		result, errorParsing := s.treeNavigator.GetMatchingNodes(newEvaluationContext(inputList, s.variables), node)
 		if errorParsing != nil {
 			return "", errorParsing
 		}